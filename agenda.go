@@ -0,0 +1,37 @@
+package revealjs
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// insertAgenda inserts the agenda slide listing the titles of the following slides after the first source.
+func (d *Deck) insertAgenda(title string) {
+	if len(d.Sources) == 0 {
+		return
+	}
+	agenda := &Slide{
+		Title:      title,
+		Attributes: map[string]string{"class": "agenda"},
+	}
+	source := newSlideSource("", SlideKindHTML, nil, "", []*Slide{agenda})
+	sources := make([]*SlideSource, 0, len(d.Sources)+1)
+	sources = append(sources, d.Sources[0], source)
+	d.Sources = append(sources, d.Sources[1:]...)
+
+	// Links to the following slides depend on the index of the agenda
+	d.updateIndices()
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<h2>%s</h2>", html.EscapeString(title))
+	sb.WriteString("<ul>")
+	for _, slide := range d.Slides() {
+		if slide.H <= agenda.H || slide.Title == "" {
+			continue
+		}
+		fmt.Fprintf(&sb, `<li><a href="%s">%s</a></li>`, html.EscapeString(slide.Link()), html.EscapeString(slide.Title))
+	}
+	sb.WriteString("</ul>")
+	agenda.Content = sb.String()
+	source.Content = fmt.Sprintf(`<section class="agenda">%s</section>`, agenda.Content)
+}
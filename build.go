@@ -0,0 +1,529 @@
+package revealjs
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FileNameManifest is the manifest of the files written by the build, placed in the destination directory.
+const FileNameManifest = ".revealcli-manifest.json"
+
+// Manifest is the list of the files written by the build.
+type Manifest struct {
+	Files []*ManifestFile `json:"files"`
+	// Pruned is the files not copied since they are not used by the slides, when 'prune' is set in the config.
+	Pruned []string `json:"pruned,omitempty"`
+	// PrunedSize is the total size of the pruned files.
+	PrunedSize int64 `json:"prunedSize,omitempty"`
+}
+
+// ManifestFile is a file written by the build.
+type ManifestFile struct {
+	// Path is the slash separated path relative to the destination directory.
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+	// SHA256 is the hex encoded hash of the content.
+	SHA256 string `json:"sha256"`
+	// Source is the fingerprint of the source file, used to reuse the file in the next build if not changed.
+	Source string `json:"source,omitempty"`
+	// Original is the path before fingerprinted if 'fingerprint' is set in the config.
+	Original string `json:"original,omitempty"`
+	// Reused is true if the file is reused from the previous build.
+	Reused bool `json:"-"`
+}
+
+// has returns true if the manifest has the file.
+func (m *Manifest) has(path string) bool {
+	return m.get(path) != nil
+}
+
+// get returns the file written at the path, or fingerprinted from the path.
+func (m *Manifest) get(path string) *ManifestFile {
+	for _, f := range m.Files {
+		if f.Path == path || f.Original == path {
+			return f
+		}
+	}
+	return nil
+}
+
+// Written returns the number of the files written by the build, not reused from the previous build.
+func (m *Manifest) Written() int {
+	n := 0
+	for _, f := range m.Files {
+		if !f.Reused {
+			n++
+		}
+	}
+	return n
+}
+
+// readManifest reads the manifest in the directory, or returns an empty manifest if not exist.
+func readManifest(dir string) (*Manifest, error) {
+	b, err := os.ReadFile(filepath.Join(dir, FileNameManifest))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Manifest{}, nil
+		}
+		return nil, err
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(b, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", FileNameManifest, err)
+	}
+	return &manifest, nil
+}
+
+// BuildDirectory returns the destination directory of the export given by 'buildDir' in the config.
+func (r *RevealJS) BuildDirectory() string {
+	dir := r.config.BuildDir
+	if dir == "" {
+		dir = DirNameBuild
+	}
+	if filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(r.dataDirectory, dir)
+}
+
+// Build exports the slides to dst, or to BuildDirectory if dst is empty.
+func (r *RevealJS) Build(dst string) error {
+	_, err := r.BuildWithManifest(dst)
+	return err
+}
+
+// buildLocal builds the deck opened from the local files in the headless Chromium, such as the thumbnails of the handout.
+// 'baseURL' is not applied, otherwise the files are loaded from the site the slides are exported to.
+func (r *RevealJS) buildLocal(dst string) error {
+	config := *r.config
+	config.BaseURL = ""
+	local := *r
+	local.config = &config
+	return local.Build(dst)
+}
+
+// BuildWithManifest exports the slides to dst, or to BuildDirectory if dst is empty, and returns the files written.
+//
+// The files are written to a temporary directory next to dst, which replaces dst after all the files are written,
+// so that dst is left as is if the build fails.
+// The files written by the previous build are removed unless written again,
+// and the other files in dst such as CNAME are kept unless 'clean' is set in the config.
+// The files whose sources are not changed since the previous build are reused unless Force is set.
+// A non-empty dst not written by the previous build is refused unless Force is set, not to replace a directory by mistake.
+func (r *RevealJS) BuildWithManifest(dst string) (*Manifest, error) {
+	if dst == "" {
+		dst = r.BuildDirectory()
+	}
+	dst, err := filepath.Abs(dst)
+	if err != nil {
+		return nil, err
+	}
+	if isWithin(dst, r.dataDirectory) {
+		return nil, fmt.Errorf("destination %s must not contain the data directory", dst)
+	}
+	if !r.Force {
+		if err := checkBuildDirectory(dst); err != nil {
+			return nil, err
+		}
+	}
+
+	parent := filepath.Dir(dst)
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return nil, err
+	}
+	tmp, err := os.MkdirTemp(parent, "."+filepath.Base(dst)+"-*")
+	if err != nil {
+		return nil, err
+	}
+	// Remove the temporary directory if failed
+	defer os.RemoveAll(tmp)
+
+	w := &buildWriter{dir: tmp, manifest: &Manifest{}, fingerprint: r.sourceFingerprint}
+	if !r.Force {
+		// Rebuild all the files if the previous manifest is broken
+		if previous, err := readManifest(dst); err == nil {
+			w.previous, w.previousDir = previous, dst
+		}
+	}
+	if err := r.writeBuild(w, dst); err != nil {
+		return nil, err
+	}
+	if r.config.FingerprintAssets() {
+		if err := fingerprintFiles(tmp, w.manifest, r.fingerprintable); err != nil {
+			return nil, fmt.Errorf("failed to fingerprint files: %w", err)
+		}
+	}
+	sort.Slice(w.manifest.Files, func(i, j int) bool {
+		return w.manifest.Files[i].Path < w.manifest.Files[j].Path
+	})
+	if !r.config.CleanBuildDir() {
+		if err := keepOtherFiles(dst, tmp, w.manifest); err != nil {
+			return nil, err
+		}
+	}
+	b, err := json.MarshalIndent(w.manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(tmp, FileNameManifest), b, 0644); err != nil {
+		return nil, err
+	}
+	// os.MkdirTemp creates the directory only for the user
+	mode := fs.FileMode(0755)
+	if info, err := os.Stat(dst); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.Chmod(tmp, mode); err != nil {
+		return nil, err
+	}
+	if err := replaceDirectory(tmp, dst); err != nil {
+		return nil, err
+	}
+	return w.manifest, nil
+}
+
+func (r *RevealJS) writeBuild(w *buildWriter, dst string) error {
+	// generate index.html
+	var indexHTML bytes.Buffer
+	if err := r.GenerateIndexHTML(&indexHTML, &HTMLGeneratorParams{
+		HotReload: false,
+		Revision:  nil,
+	}); err != nil {
+		return err
+	}
+	if err := w.writeFile(FileNameIndexHTML, "", func(writer io.Writer) error {
+		_, err := writer.Write(indexHTML.Bytes())
+		return err
+	}); err != nil {
+		return err
+	}
+
+	skip := func(path string) bool {
+		// Skip paths under dst directory
+		absSrc := filepath.Join(r.dataDirectory, path)
+		if strings.HasPrefix(absSrc, dst) {
+			return true
+		}
+
+		// Skip templates and config.yml
+		filename := filepath.Base(path)
+		if filename == FileNameIndexHTMLTmpl || filename == FileNameHandoutHTMLTmpl || filename == FileNameConfig {
+			return true
+		}
+
+		// Skip embedded html/md files
+		if r.EmbedHTML && IsHTML(filename) {
+			return true
+		}
+		if r.EmbedMarkdown && IsMarkdown(filename) {
+			return true
+		}
+		return false
+	}
+
+	// Skip the files not referenced from index.html
+	if r.config.PruneAssets() {
+		unused, err := r.unusedFiles(indexHTML.Bytes(), skip)
+		if err != nil {
+			return err
+		}
+		pruned := make(map[string]bool, len(unused))
+		for _, file := range unused {
+			pruned[file.Path] = true
+			w.manifest.Pruned = append(w.manifest.Pruned, file.Path)
+			w.manifest.PrunedSize += file.Size
+		}
+		copySkip := skip
+		skip = func(path string) bool {
+			return copySkip(path) || pruned[path]
+		}
+	}
+
+	// copy fs files
+	return extractFile(r.fs, ".", w, skip)
+}
+
+// sourceFingerprint returns the fingerprint of the source file changing when the file is changed.
+// The fingerprint of the user file is its size and modification time not to read the large assets,
+// and the fingerprint of the embedded file is the hash of its content.
+func (r *RevealJS) sourceFingerprint(path string) (string, error) {
+	if info, err := fs.Stat(r.userFS, path); err == nil {
+		return fmt.Sprintf("%d-%d", info.Size(), info.ModTime().UnixNano()), nil
+	}
+	if hash, ok := embeddedFileHashes.Load(path); ok {
+		return hash.(string), nil
+	}
+	f, err := r.fs.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	fingerprint := "sha256:" + hex.EncodeToString(hash.Sum(nil))
+	embeddedFileHashes.Store(path, fingerprint)
+	return fingerprint, nil
+}
+
+// embeddedFileHashes caches the fingerprints of the embedded files, which are not changed while running.
+var embeddedFileHashes sync.Map
+
+// buildWriter writes the files of the build to the directory and records them to the manifest.
+type buildWriter struct {
+	dir      string
+	manifest *Manifest
+	// fingerprint returns the fingerprint of the source file.
+	fingerprint func(path string) (string, error)
+	// previous is the manifest of the previous build in previousDir, nil if not reused.
+	previous    *Manifest
+	previousDir string
+}
+
+// reuseFile links the file of the previous build if its source is not changed, and returns true if reused.
+func (w *buildWriter) reuseFile(path string, source string) bool {
+	if w.previous == nil {
+		return false
+	}
+	previous := w.previous.get(path)
+	if previous == nil || previous.Source == "" || previous.Source != source {
+		return false
+	}
+	src := filepath.Join(w.previousDir, filepath.FromSlash(previous.Path))
+	if info, err := os.Stat(src); err != nil || info.Size() != previous.Size {
+		return false
+	}
+	if err := linkFile(src, filepath.Join(w.dir, filepath.FromSlash(path))); err != nil {
+		return false
+	}
+	// The file is fingerprinted again after the build
+	reused := *previous
+	reused.Path, reused.Original, reused.Reused = path, "", true
+	w.manifest.Files = append(w.manifest.Files, &reused)
+	return true
+}
+
+// writeFile writes the file at the slash separated path relative to the directory.
+// source is the fingerprint of the source file, or empty string if the file is generated.
+func (w *buildWriter) writeFile(path string, source string, write func(writer io.Writer) error) error {
+	dst := filepath.Join(w.dir, filepath.FromSlash(path))
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	hash := sha256.New()
+	if err := write(io.MultiWriter(f, hash)); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	info, err := os.Stat(dst)
+	if err != nil {
+		return err
+	}
+	w.manifest.Files = append(w.manifest.Files, &ManifestFile{
+		Path:   path,
+		Size:   info.Size(),
+		SHA256: hex.EncodeToString(hash.Sum(nil)),
+		Source: source,
+	})
+	return nil
+}
+
+// extractFile copies files from src to the build.
+// src is a path from the root of the file system
+func extractFile(fileSystem fs.FS, src string, w *buildWriter, skip func(path string) bool) error {
+	return fs.WalkDir(fileSystem, src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if skip(path) {
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		relPath, _ := filepath.Rel(src, path)
+		relPath = filepath.ToSlash(relPath)
+		source, err := w.fingerprint(path)
+		if err != nil {
+			return err
+		}
+		if w.reuseFile(relPath, source) {
+			return nil
+		}
+		reader, err := fileSystem.Open(path)
+		if err != nil {
+			return err
+		}
+		defer reader.Close()
+
+		return w.writeFile(relPath, source, func(writer io.Writer) error {
+			if IsMarkdown(path) {
+				b, err := io.ReadAll(reader)
+				if err != nil {
+					return err
+				}
+				content := NewMarkdown(string(b)).WithoutYAMLHeader()
+				_, err = io.WriteString(writer, content)
+				return err
+			}
+			_, err := io.Copy(writer, reader)
+			return err
+		})
+	})
+}
+
+// keepOtherFiles links the files in dst which are not written by the builds to tmp.
+func keepOtherFiles(dst, tmp string, manifest *Manifest) error {
+	previous, err := readManifest(dst)
+	if err != nil {
+		return err
+	}
+	if !exist(dst) {
+		return nil
+	}
+	// The directories of the previous build are not kept if empty
+	previousDirs := map[string]bool{}
+	for _, f := range previous.Files {
+		for dir := path.Dir(f.Path); dir != "."; dir = path.Dir(dir) {
+			previousDirs[dir] = true
+		}
+	}
+	return filepath.WalkDir(dst, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dst, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == "." {
+			return nil
+		}
+		target := filepath.Join(tmp, filepath.FromSlash(rel))
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			if previousDirs[rel] {
+				return nil
+			}
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+			return os.Chmod(target, info.Mode().Perm())
+		case rel == FileNameManifest || previous.has(rel) || manifest.has(rel):
+			return nil
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		}
+		if err := linkFile(p, target); err != nil {
+			return err
+		}
+		return os.Chmod(target, info.Mode().Perm())
+	})
+}
+
+// checkBuildDirectory returns an error if dir is not empty and not written by the build.
+// The builds exported before the manifest was written are adopted by their index.html and dist/reveal.js.
+func checkBuildDirectory(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 || exist(filepath.Join(dir, FileNameManifest)) {
+		return nil
+	}
+	if exist(filepath.Join(dir, FileNameIndexHTML)) && exist(filepath.Join(dir, "dist", "reveal.js")) {
+		return nil
+	}
+	return fmt.Errorf("destination %s is not empty and not written by export, --force is required to replace it", dir)
+}
+
+// isWithin returns true if path is dir or in dir.
+func isWithin(dir string, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+// linkFile links or copies the file.
+func linkFile(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// replaceDirectory replaces dst with src.
+func replaceDirectory(src, dst string) error {
+	if !exist(dst) {
+		return os.Rename(src, dst)
+	}
+	backup, err := os.MkdirTemp(filepath.Dir(dst), "."+filepath.Base(dst)+"-old-*")
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(backup); err != nil {
+		return err
+	}
+	if err := os.Rename(dst, backup); err != nil {
+		return err
+	}
+	if err := os.Rename(src, dst); err != nil {
+		// Restore dst
+		os.Rename(backup, dst)
+		return err
+	}
+	return os.RemoveAll(backup)
+}
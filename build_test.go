@@ -0,0 +1,362 @@
+package revealjs
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildReplacesDestination(t *testing.T) {
+	dataDir := t.TempDir()
+	dst := filepath.Join(t.TempDir(), "build")
+	writeFile(t, filepath.Join(dataDir, "slides.md"), "# Slide\n")
+	if err := os.MkdirAll(filepath.Join(dataDir, "assets"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dataDir, "assets", "old.png"), "old")
+
+	build := func() *Manifest {
+		t.Helper()
+		r, err := NewRevealJS(dataDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		manifest, err := r.BuildWithManifest(dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return manifest
+	}
+	manifest := build()
+	assertEqual(t, manifest.has("index.html"), true)
+	assertEqual(t, manifest.has("assets/old.png"), true)
+	assertExist(t, filepath.Join(dst, FileNameManifest), true)
+
+	// The files written by the previous build are removed, and the other files are kept
+	writeFile(t, filepath.Join(dst, "CNAME"), "example.com")
+	if err := os.Rename(filepath.Join(dataDir, "assets", "old.png"), filepath.Join(dataDir, "assets", "new.png")); err != nil {
+		t.Fatal(err)
+	}
+	manifest = build()
+	assertEqual(t, manifest.has("assets/old.png"), false)
+	assertExist(t, filepath.Join(dst, "assets", "old.png"), false)
+	assertExist(t, filepath.Join(dst, "assets", "new.png"), true)
+	assertExist(t, filepath.Join(dst, "CNAME"), true)
+
+	// clean removes the other files
+	writeFile(t, filepath.Join(dataDir, "config.yml"), "clean: true\n")
+	build()
+	assertExist(t, filepath.Join(dst, "CNAME"), false)
+	assertExist(t, filepath.Join(dst, "index.html"), true)
+
+	// The destination is left as is if the build fails
+	writeFile(t, filepath.Join(dataDir, "index.html.tmpl"), "{{ .unclosed ")
+	r, err := NewRevealJS(dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Build(dst); err == nil {
+		t.Fatal("expected the build to fail")
+	}
+	assertExist(t, filepath.Join(dst, "index.html"), true)
+	entries, err := os.ReadDir(filepath.Dir(dst))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, len(entries), 1)
+}
+
+func TestBuildRefusesDataDirectory(t *testing.T) {
+	dataDir := t.TempDir()
+	writeFile(t, filepath.Join(dataDir, "slides.md"), "# Slide\n")
+	r, err := NewRevealJS(dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Build(dataDir); err == nil {
+		t.Error("expected error for the data directory")
+	}
+	assertExist(t, filepath.Join(dataDir, "slides.md"), true)
+}
+
+func TestBuildLocalWithoutBaseURL(t *testing.T) {
+	dataDir := t.TempDir()
+	writeFile(t, filepath.Join(dataDir, "slides.md"), "# Slide\n")
+	writeFile(t, filepath.Join(dataDir, FileNameConfig), "baseURL: https://example.com/talks/\n")
+	r, err := NewRevealJS(dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst := filepath.Join(t.TempDir(), "local")
+	if err := r.buildLocal(dst); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(filepath.Join(dst, FileNameIndexHTML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, strings.Contains(string(b), "<base"), false)
+	// The config is not changed
+	assertEqual(t, r.config.BaseURL, "https://example.com/talks/")
+}
+
+func TestBuildRefusesNonBuildDirectory(t *testing.T) {
+	dataDir := t.TempDir()
+	dst := t.TempDir()
+	writeFile(t, filepath.Join(dataDir, "slides.md"), "# Slide\n")
+	writeFile(t, filepath.Join(dataDir, "config.yml"), "clean: true\n")
+	writeFile(t, filepath.Join(dst, "notes.txt"), "important")
+	r, err := NewRevealJS(dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Build(dst); err == nil {
+		t.Error("expected error for the directory not written by the build")
+	}
+	assertExist(t, filepath.Join(dst, "notes.txt"), true)
+
+	r.Force = true
+	if err := r.Build(dst); err != nil {
+		t.Fatal(err)
+	}
+	assertExist(t, filepath.Join(dst, "notes.txt"), false)
+	assertExist(t, filepath.Join(dst, "index.html"), true)
+}
+
+func TestBuildAdoptsBuildWithoutManifest(t *testing.T) {
+	dataDir := t.TempDir()
+	dst := t.TempDir()
+	writeFile(t, filepath.Join(dataDir, "slides.md"), "# Slide\n")
+	// Exported before the manifest was written
+	writeFile(t, filepath.Join(dst, "index.html"), "old")
+	if err := os.MkdirAll(filepath.Join(dst, "dist"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dst, "dist", "reveal.js"), "old")
+	writeFile(t, filepath.Join(dst, "CNAME"), "example.com")
+	r, err := NewRevealJS(dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Build(dst); err != nil {
+		t.Fatal(err)
+	}
+	assertExist(t, filepath.Join(dst, FileNameManifest), true)
+	assertExist(t, filepath.Join(dst, "CNAME"), true)
+	b, err := os.ReadFile(filepath.Join(dst, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, string(b) != "old", true)
+}
+
+func TestBuildKeepsOtherFiles(t *testing.T) {
+	dataDir := t.TempDir()
+	dst := filepath.Join(t.TempDir(), "build")
+	writeFile(t, filepath.Join(dataDir, "slides.md"), "# Slide\n")
+	r, err := NewRevealJS(dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Build(dst); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(dst); err != nil || info.Mode().Perm() != 0755 {
+		t.Errorf("expected the destination to be created with 0755: %v %v", info, err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dst, "empty"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dst, "deploy.sh"), "#!/bin/sh\n")
+	if err := os.Chmod(filepath.Join(dst, "deploy.sh"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("index.html", filepath.Join(dst, "home.html")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(dst, 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Build(dst); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(dst); err != nil || info.Mode().Perm() != 0750 {
+		t.Errorf("expected the mode of the destination to be kept: %v %v", info, err)
+	}
+	if info, err := os.Stat(filepath.Join(dst, "empty")); err != nil || !info.IsDir() || info.Mode().Perm() != 0750 {
+		t.Errorf("expected the empty directory to be kept: %v %v", info, err)
+	}
+	if info, err := os.Stat(filepath.Join(dst, "deploy.sh")); err != nil || info.Mode().Perm() != 0755 {
+		t.Errorf("expected the mode of the file to be kept: %v %v", info, err)
+	}
+	if link, err := os.Readlink(filepath.Join(dst, "home.html")); err != nil || link != "index.html" {
+		t.Errorf("expected the symlink to be kept: %s %v", link, err)
+	}
+}
+
+func TestIsWithin(t *testing.T) {
+	for _, c := range []struct {
+		dir, path string
+		expected  bool
+	}{
+		{"/a", "/a", true},
+		{"/a", "/a/b", true},
+		{"/a/b", "/a", false},
+		{"/a", "/b", false},
+		{"/a", "/a/..data", true},
+		{"/a/b", "/a/..b", false},
+	} {
+		assertEqual(t, isWithin(filepath.FromSlash(c.dir), filepath.FromSlash(c.path)), c.expected)
+	}
+}
+
+func assertExist(t *testing.T, path string, expected bool) {
+	t.Helper()
+	if exist(path) != expected {
+		t.Errorf("expected exist(%s) to be %v", path, expected)
+	}
+}
+
+func TestBuildReusesUnchangedFiles(t *testing.T) {
+	dataDir := t.TempDir()
+	dst := filepath.Join(t.TempDir(), "build")
+	writeFile(t, filepath.Join(dataDir, "slides.md"), "# Slide\n")
+	if err := os.MkdirAll(filepath.Join(dataDir, "assets"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	image := filepath.Join(dataDir, "assets", "image.png")
+	writeFile(t, image, "image")
+
+	build := func(force bool) *Manifest {
+		t.Helper()
+		r, err := NewRevealJS(dataDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.Force = force
+		manifest, err := r.BuildWithManifest(dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return manifest
+	}
+	manifest := build(false)
+	assertEqual(t, manifest.Written(), len(manifest.Files))
+
+	// Only index.html is written
+	manifest = build(false)
+	assertEqual(t, manifest.Written(), 1)
+	assertEqual(t, manifest.get("assets/image.png").Reused, true)
+
+	// The changed file is written
+	writeFile(t, image, "changed image")
+	manifest = build(false)
+	assertEqual(t, manifest.Written(), 2)
+	assertEqual(t, manifest.get("assets/image.png").Reused, false)
+	b, err := os.ReadFile(filepath.Join(dst, "assets", "image.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, string(b), "changed image")
+
+	// Force writes all the files
+	manifest = build(true)
+	assertEqual(t, manifest.Written(), len(manifest.Files))
+}
+
+func TestBuildPrunesWithExternalMarkdown(t *testing.T) {
+	dataDir := t.TempDir()
+	dst := filepath.Join(t.TempDir(), "build")
+	writeFile(t, filepath.Join(dataDir, "config.yml"), "prune: true\n")
+	writeFile(t, filepath.Join(dataDir, "slides.md"), "# Slide\n\n![Used](assets/used.png)\n")
+	if err := os.MkdirAll(filepath.Join(dataDir, "assets"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dataDir, "assets", "used.png"), "used")
+	writeFile(t, filepath.Join(dataDir, "assets", "unused.png"), "unused")
+	r, err := NewRevealJS(dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The markdown is loaded by reveal.js, and only the markdown references the image
+	r.EmbedMarkdown = false
+	if err := r.Build(dst); err != nil {
+		t.Fatal(err)
+	}
+	assertExist(t, filepath.Join(dst, "slides.md"), true)
+	assertExist(t, filepath.Join(dst, "assets", "used.png"), true)
+	assertExist(t, filepath.Join(dst, "assets", "unused.png"), false)
+}
+
+func TestBuildFingerprintsAssets(t *testing.T) {
+	dataDir := t.TempDir()
+	dst := filepath.Join(t.TempDir(), "build")
+	writeFile(t, filepath.Join(dataDir, "config.yml"), "fingerprint: true\nplugins: !append [{name: RevealChart, src: assets/chart/chart.js}]\n")
+	writeFile(t, filepath.Join(dataDir, "slides.md"), "# Slide\n\n![](assets/image.png)\n")
+	if err := os.MkdirAll(filepath.Join(dataDir, "assets", "chart"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	// The plugin loads the sibling files by their names
+	chartJS := "import('./chart-lib.js');\n//# sourceMappingURL=chart.js.map\n"
+	writeFile(t, filepath.Join(dataDir, "assets", "chart", "chart.js"), chartJS)
+	writeFile(t, filepath.Join(dataDir, "assets", "chart", "chart-lib.js"), "lib")
+	writeFile(t, filepath.Join(dataDir, "assets", "chart", "chart.js.map"), "{}")
+	writeFile(t, filepath.Join(dataDir, "assets", "chart", "chart.css"), ".chart {}")
+	writeFile(t, filepath.Join(dataDir, "assets", "image.png"), "image")
+	writeFile(t, filepath.Join(dataDir, "assets", "my-image.png"), "my image")
+	writeFile(t, filepath.Join(dataDir, "assets", "custom.css"), ".a { background: url(image.png); }\n.b { background: url('./my-image.png'); }\n")
+
+	build := func() *Manifest {
+		t.Helper()
+		r, err := NewRevealJS(dataDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		manifest, err := r.BuildWithManifest(dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return manifest
+	}
+	read := func(path string) string {
+		t.Helper()
+		b, err := os.ReadFile(filepath.Join(dst, filepath.FromSlash(path)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+	manifest := build()
+	image := manifest.get("assets/image.png")
+	myImage := manifest.get("assets/my-image.png")
+	css := manifest.get("assets/custom.css")
+	assertEqual(t, image.Path, "assets/image."+image.SHA256[:fingerprintLength]+".png")
+	assertEqual(t, image.Original, "assets/image.png")
+	assertEqual(t, css.Path, "assets/custom."+css.SHA256[:fingerprintLength]+".css")
+	assertExist(t, filepath.Join(dst, "assets", "image.png"), false)
+	assertEqual(t, read(css.Path), ".a { background: url("+path.Base(image.Path)+"); }\n.b { background: url('./"+path.Base(myImage.Path)+"'); }\n")
+	assertEqual(t, strings.Contains(read("index.html"), `href="`+manifest.get("dist/theme/black.css").Path+`"`), true)
+	// The markdown is fetched by its name, and its references are rewritten
+	assertEqual(t, read("slides.md"), "# Slide\n\n![]("+image.Path+")\n")
+	assertEqual(t, manifest.get("index.html").Original, "")
+	// The plugin script is renamed, but not the files it loads
+	chart := manifest.get("assets/chart/chart.js")
+	assertEqual(t, chart.Path, "assets/chart/chart."+chart.SHA256[:fingerprintLength]+".js")
+	assertEqual(t, strings.Contains(read("index.html"), `src="`+chart.Path+`"`), true)
+	assertEqual(t, read(chart.Path), chartJS)
+	assertEqual(t, manifest.get("assets/chart/chart-lib.js").Path, "assets/chart/chart-lib.js")
+	assertEqual(t, manifest.get("assets/chart/chart.js.map").Path, "assets/chart/chart.js.map")
+	assertEqual(t, manifest.get("assets/chart/chart.css").Path, "assets/chart/chart.css")
+	assertEqual(t, manifest.get("dist/reveal.js").Path, "dist/reveal."+manifest.get("dist/reveal.js").SHA256[:fingerprintLength]+".js")
+	assertEqual(t, manifest.get("plugin/markdown/markdown.js").Path, "plugin/markdown/markdown."+manifest.get("plugin/markdown/markdown.js").SHA256[:fingerprintLength]+".js")
+
+	// The fingerprinted files are reused in the next build
+	manifest = build()
+	assertEqual(t, manifest.get("assets/image.png").Path, image.Path)
+	assertEqual(t, manifest.get("assets/image.png").Reused, true)
+	assertEqual(t, manifest.get("assets/custom.css").Path, css.Path)
+	assertExist(t, filepath.Join(dst, filepath.FromSlash(image.Path)), true)
+}
@@ -0,0 +1,242 @@
+package revealjs
+
+import (
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	htmlReferenceRegexp     = regexp.MustCompile(`(?i)\b(src|href|poster|data-src|data-background-image|data-background-video|data-background-iframe|data-external|data-markdown)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	markdownLinkRegexp      = regexp.MustCompile(`(!?)\[(?:[^\[\]]|\[[^\]]*\])*\]\(\s*(?:<([^>]*)>|([^\s)]+))`)
+	markdownLinkDefRegexp   = regexp.MustCompile(`(?m)^ {0,3}\[[^\]]+\]:[ \t]*(?:<([^>]*)>|(\S+))`)
+	markdownCodeFenceRegexp = regexp.MustCompile("(?ms)^ {0,3}```.*?(?:^ {0,3}```[ \t]*$|\\z)")
+	markdownCodeSpanRegexp  = regexp.MustCompile("`[^`\n]+`")
+)
+
+// Problem is a problem found in the slides, located by the file relative to the data directory and the line or the slide.
+type Problem struct {
+	File string `json:"file"`
+	// Line is 1-based, or 0 if unknown.
+	Line int `json:"line,omitempty"`
+	// Slide is the slide number such as "3.2" found by Lint.
+	Slide   string `json:"slide,omitempty"`
+	Message string `json:"message"`
+	// Rule and Severity are the lint rule finding the problem and its severity.
+	Rule     string       `json:"rule,omitempty"`
+	Severity LintSeverity `json:"severity,omitempty"`
+}
+
+func (p *Problem) String() string {
+	location := p.File
+	if p.Line > 0 {
+		location += fmt.Sprintf(":%d", p.Line)
+	}
+	if p.Slide != "" {
+		location += fmt.Sprintf(" (slide %s)", p.Slide)
+	}
+	message := p.Message
+	if p.Severity != "" {
+		message = fmt.Sprintf("%s: %s", p.Severity, message)
+	}
+	if p.Rule != "" {
+		message += fmt.Sprintf(" [%s]", p.Rule)
+	}
+	return fmt.Sprintf("%s: %s", location, message)
+}
+
+// LinkCheckOptions is the options of CheckLinks.
+type LinkCheckOptions struct {
+	// AllowedHosts is the patterns of the hosts of the external URLs such as '*.example.com'.
+	// All the external URLs are allowed if empty.
+	AllowedHosts []string
+}
+
+// CheckLinks checks that the entries of 'slides' in the config match the slide files,
+// and that the files referenced from the slides exist in the file system of the export,
+// such as the images and the links in markdown, src, href and data-background-image attributes,
+// and the files of data-external and data-markdown.
+// The external URLs are not fetched, but checked against the allowed hosts.
+func (r *RevealJS) CheckLinks(options *LinkCheckOptions) ([]*Problem, error) {
+	if options == nil {
+		options = &LinkCheckOptions{}
+	}
+	problems, files, err := r.checkSlidePatterns()
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		b, err := fs.ReadFile(r.userFS, file)
+		if err != nil {
+			return nil, err
+		}
+		content := string(b)
+		// Report the broken front matter or HTML as is
+		if _, err := r.loadSlideSource(file); err != nil {
+			problems = append(problems, &Problem{File: file, Message: fmt.Sprintf("failed to load slide file: %s", err)})
+			continue
+		}
+		for _, ref := range slideReferences(file, content) {
+			if message := r.checkReference(ref, options); message != "" {
+				problems = append(problems, &Problem{File: file, Line: ref.line, Message: message})
+			}
+		}
+	}
+	sortProblems(problems)
+	return problems, nil
+}
+
+// checkSlidePatterns checks the entries of 'slides' in the config, and returns the slide files matching the valid entries.
+func (r *RevealJS) checkSlidePatterns() ([]*Problem, []string, error) {
+	problems := make([]*Problem, 0)
+	if len(r.config.Slides) == 0 {
+		files, err := r.slideFilesIn(".")
+		return problems, files, err
+	}
+	files, errs := r.expandSlidePatterns(r.config.Slides)
+	for _, err := range errs {
+		problems = append(problems, r.configProblem("slides", err.Pattern, err.Error()))
+	}
+	return problems, files, nil
+}
+
+// configProblem returns the problem located at the line of the value in the file where the config key is set.
+func (r *RevealJS) configProblem(key string, value string, message string) *Problem {
+	problem := &Problem{File: r.ConfigSource(key), Message: message}
+	file := r.config.Source(key)
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(r.dataDirectory, file)
+	}
+	if b, err := os.ReadFile(file); err == nil {
+		for i, line := range strings.Split(string(b), "\n") {
+			if strings.Contains(line, value) {
+				problem.Line = i + 1
+				break
+			}
+		}
+	}
+	return problem
+}
+
+// slideReference is a reference to a file or a URL in the slide file.
+type slideReference struct {
+	// kind is the attribute name such as 'src', or 'image' and 'link' for markdown.
+	kind string
+	ref  string
+	line int
+}
+
+// slideReferences returns the references in the HTML or markdown slide file.
+// The code blocks and the code spans in markdown are ignored.
+func slideReferences(file string, content string) []*slideReference {
+	if IsMarkdown(file) {
+		content = markdownCodeFenceRegexp.ReplaceAllStringFunc(content, blankOut)
+		content = markdownCodeSpanRegexp.ReplaceAllStringFunc(content, blankOut)
+	}
+	refs := make([]*slideReference, 0)
+	add := func(kind string, content string, match []int, group ...int) {
+		for _, g := range group {
+			if start, end := match[2*g], match[2*g+1]; start >= 0 {
+				refs = append(refs, &slideReference{kind, content[start:end], 1 + strings.Count(content[:start], "\n")})
+				return
+			}
+		}
+	}
+	for _, match := range htmlReferenceRegexp.FindAllStringSubmatchIndex(content, -1) {
+		add(strings.ToLower(content[match[2]:match[3]]), content, match, 2, 3)
+	}
+	if IsMarkdown(file) {
+		for _, match := range markdownLinkRegexp.FindAllStringSubmatchIndex(content, -1) {
+			kind := "link"
+			if match[3] > match[2] {
+				kind = "image"
+			}
+			add(kind, content, match, 2, 3)
+		}
+		for _, match := range markdownLinkDefRegexp.FindAllStringSubmatchIndex(content, -1) {
+			add("link", content, match, 1, 2)
+		}
+	}
+	sort.SliceStable(refs, func(i, j int) bool {
+		return refs[i].line < refs[j].line
+	})
+	return refs
+}
+
+// blankOut replaces the characters except the line breaks with spaces to keep the line numbers.
+func blankOut(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\n' {
+			return r
+		}
+		return ' '
+	}, s)
+}
+
+// checkReference returns the problem of the reference, or empty string if the reference is valid.
+// The relative paths are resolved from the root of the export, where the slides are rendered in index.html.
+func (r *RevealJS) checkReference(ref *slideReference, options *LinkCheckOptions) string {
+	s := strings.TrimSpace(ref.ref)
+	if s == "" {
+		// data-markdown without the value has the embedded markdown
+		if ref.kind == "data-markdown" {
+			return ""
+		}
+		return fmt.Sprintf("empty %s", ref.kind)
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return fmt.Sprintf("invalid %s '%s': %s", ref.kind, s, err)
+	}
+	if u.Host != "" || u.Scheme == "http" || u.Scheme == "https" {
+		if !allowedHost(u.Hostname(), options.AllowedHosts) {
+			return fmt.Sprintf("%s '%s' is not an allowed external URL", ref.kind, s)
+		}
+		return ""
+	}
+	if u.Scheme != "" || u.Path == "" {
+		// mailto:, data: or the links to the slides such as '#/2'
+		return ""
+	}
+	p := path.Clean(strings.TrimPrefix(u.Path, "/"))
+	if p == ".." || strings.HasPrefix(p, "../") {
+		return fmt.Sprintf("%s '%s' is outside of the data directory", ref.kind, s)
+	}
+	if _, err := fs.Stat(r.fs, p); err != nil {
+		if ref.kind == "data-external" || ref.kind == "data-markdown" {
+			return fmt.Sprintf("slide file '%s' of %s not found", s, ref.kind)
+		}
+		return fmt.Sprintf("%s '%s' not found", ref.kind, s)
+	}
+	return ""
+}
+
+func allowedHost(host string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(strings.ToLower(pattern), strings.ToLower(host)); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func sortProblems(problems []*Problem) {
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].File != problems[j].File {
+			return problems[i].File < problems[j].File
+		}
+		if problems[i].Line != problems[j].Line {
+			return problems[i].Line < problems[j].Line
+		}
+		return naturalLess(problems[i].Slide, problems[j].Slide)
+	})
+}
@@ -0,0 +1,60 @@
+package revealjs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckLinks(t *testing.T) {
+	dataDir := t.TempDir()
+	for _, dir := range []string{"assets", "slides"} {
+		if err := os.MkdirAll(filepath.Join(dataDir, dir), 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(t, filepath.Join(dataDir, "assets", "ok.png"), "ok")
+	writeFile(t, filepath.Join(dataDir, "config.yml"), "slides:\n  - intro.md\n  - missing.md\n  - slides\n")
+	writeFile(t, filepath.Join(dataDir, "intro.md"), "---\ntitle: Intro\n---\n# Intro\n\n"+
+		"![ok](assets/ok.png) ![missing](assets/shot.png \"Shot\")\n"+
+		"[site](https://example.com/) [mail](mailto:a@example.com) [slide](#/2) [theme](dist/theme/black.css)\n"+
+		"\n```html\n<img src=\"assets/in-code.png\">\n```\n\n"+
+		"`<img src=\"assets/in-span.png\">`\n"+
+		"<!-- .slide: data-background-image=\"assets/bg.png\" -->\n"+
+		"[ref]: ../secret.txt\n")
+	writeFile(t, filepath.Join(dataDir, "slides", "a.html"), "<section>\n"+
+		"  <img src='assets/ok.png?v=1'>\n"+
+		"  <img src=\"assets/gone.jpg\">\n"+
+		"  <section data-external=\"other.html\"></section>\n"+
+		"  <a href=\"https://other.example.org/\">other</a>\n"+
+		"</section>\n")
+
+	r, err := NewRevealJS(dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	problems, err := r.CheckLinks(&LinkCheckOptions{AllowedHosts: []string{"example.com"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := make([]string, 0, len(problems))
+	for _, problem := range problems {
+		actual = append(actual, problem.String())
+	}
+	assertEqual(t, actual, []string{
+		"config.yml:3: no slide files match 'missing.md' in slides",
+		"intro.md:6: image 'assets/shot.png' not found",
+		"intro.md:14: data-background-image 'assets/bg.png' not found",
+		"intro.md:15: link '../secret.txt' is outside of the data directory",
+		"slides/a.html:3: src 'assets/gone.jpg' not found",
+		"slides/a.html:4: slide file 'other.html' of data-external not found",
+		"slides/a.html:5: href 'https://other.example.org/' is not an allowed external URL",
+	})
+
+	// All the external URLs are allowed by default
+	problems, err = r.CheckLinks(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, len(problems), 6)
+}
@@ -0,0 +1,115 @@
+package revealjs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// EnvChromium is the environment variable to specify the path of the Chromium executable.
+const EnvChromium = "REVEALCLI_CHROMIUM"
+
+var chromiumExecutables = []string{
+	"chromium",
+	"chromium-browser",
+	"google-chrome",
+	"google-chrome-stable",
+	"chrome",
+	"/Applications/Google Chrome.app/Contents/MacOS/Google Chrome",
+	"/Applications/Chromium.app/Contents/MacOS/Chromium",
+}
+
+// Chromium runs a headless Chromium (or Chrome) browser.
+type Chromium struct {
+	Path string
+}
+
+// FindChromium finds the Chromium executable from $REVEALCLI_CHROMIUM or $PATH.
+func FindChromium() (*Chromium, error) {
+	if path := os.Getenv(EnvChromium); path != "" {
+		return &Chromium{path}, nil
+	}
+	for _, name := range chromiumExecutables {
+		if path, err := exec.LookPath(name); err == nil {
+			return &Chromium{path}, nil
+		}
+	}
+	return nil, fmt.Errorf("chromium not found, install it or set $%s", EnvChromium)
+}
+
+// Screenshot captures the page at url into a PNG file.
+func (c *Chromium) Screenshot(url string, dst string, width int, height int) error {
+	dst, err := filepath.Abs(dst)
+	if err != nil {
+		return err
+	}
+	return c.run(
+		"--hide-scrollbars",
+		fmt.Sprintf("--window-size=%d,%d", width, height),
+		"--screenshot="+dst,
+		url,
+	)
+}
+
+// PrintToPDF prints the page at url into a PDF file.
+func (c *Chromium) PrintToPDF(url string, dst string) error {
+	dst, err := filepath.Abs(dst)
+	if err != nil {
+		return err
+	}
+	return c.run(
+		"--no-pdf-header-footer",
+		"--print-to-pdf="+dst,
+		url,
+	)
+}
+
+// DumpDOM returns the serialized DOM of the page at url after the scripts run.
+func (c *Chromium) DumpDOM(url string, width int, height int) (string, error) {
+	out, err := c.command(
+		fmt.Sprintf("--window-size=%d,%d", width, height),
+		"--dump-dom",
+		url,
+	).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", fmt.Errorf("chromium failed: %w: %s", err, exitErr.Stderr)
+		}
+		return "", fmt.Errorf("chromium failed: %w", err)
+	}
+	return string(out), nil
+}
+
+func (c *Chromium) run(args ...string) error {
+	if out, err := c.command(args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("chromium failed: %w: %s", err, out)
+	}
+	return nil
+}
+
+func (c *Chromium) command(args ...string) *exec.Cmd {
+	baseArgs := []string{
+		"--headless",
+		"--disable-gpu",
+		"--allow-file-access-from-files",
+		// Give reveal.js and its plugins time to lay out the slides.
+		"--virtual-time-budget=5000",
+	}
+	// Chromium refuses to start as root with the sandbox enabled, which is common in CI containers.
+	if os.Geteuid() == 0 {
+		baseArgs = append(baseArgs, "--no-sandbox")
+	}
+	return exec.Command(c.Path, append(baseArgs, args...)...)
+}
+
+// fileURL returns the file:// URL of the local path.
+func fileURL(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return "file://" + filepath.ToSlash(abs), nil
+}
@@ -1,11 +1,13 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
 
@@ -16,6 +18,11 @@ import (
 var version string = "dev"
 
 func main() {
+	os.Exit(run(os.Args))
+}
+
+// run runs the command with the arguments and returns the exit status.
+func run(args []string) int {
 	app := cli.NewApp()
 	app.Version = version
 	app.Usage = "presentation slide generator using reveal.js"
@@ -29,6 +36,8 @@ func main() {
 		},
 	}
 	app.DefaultCommand = "start"
+	// The exit status is returned by run, instead of exiting in app.Run
+	app.ExitErrHandler = func(ctx *cli.Context, err error) {}
 
 	var revealJS *revealjs.RevealJS
 	app.Before = func(ctx *cli.Context) error {
@@ -41,6 +50,47 @@ func main() {
 		if err != nil {
 			return fmt.Errorf("failed to initialize app: %s", err)
 		}
+		if overrides := revealjs.ConfigOverridesFromEnv(os.Environ()); len(overrides) > 0 {
+			if err := revealJS.OverrideConfig(overrides...); err != nil {
+				return fmt.Errorf("failed to initialize app: %s", err)
+			}
+		}
+		return nil
+	}
+	configFlags := []cli.Flag{
+		&cli.StringFlag{
+			Name:  "profile",
+			Usage: "name of the profile defined in 'profiles' of config.yml",
+		},
+		&cli.StringSliceFlag{
+			Name:  "set",
+			Usage: "override the config value such as 'revealjs.controls=false'",
+		},
+	}
+	configure := func(ctx *cli.Context) error {
+		if name := ctx.String("profile"); name != "" {
+			if err := revealJS.UseProfile(name); err != nil {
+				return err
+			}
+		}
+		if sets := ctx.StringSlice("set"); len(sets) > 0 {
+			overrides := make([]revealjs.ConfigOverride, 0, len(sets))
+			for _, set := range sets {
+				override, err := revealjs.ParseConfigOverride(set)
+				if err != nil {
+					return err
+				}
+				overrides = append(overrides, override)
+			}
+			return revealJS.OverrideConfig(overrides...)
+		}
+		return nil
+	}
+	// The commands writing or listing the slides fail on the broken 'slides', which are skipped in the deck
+	checkSlides := func() error {
+		if err := revealJS.SlidesError(); err != nil {
+			return fmt.Errorf("broken 'slides' in config.yml: %w", err)
+		}
 		return nil
 	}
 	app.Commands = []*cli.Command{
@@ -83,7 +133,7 @@ func main() {
 		{
 			Name:  "start",
 			Usage: "Start reveal.js server",
-			Flags: []cli.Flag{
+			Flags: append([]cli.Flag{
 				&cli.IntFlag{
 					Name:    "port",
 					Aliases: []string{"p"},
@@ -95,8 +145,13 @@ func main() {
 					Usage:   "open browser",
 					Value:   true,
 				},
-			},
+			}, configFlags...),
 			Action: func(ctx *cli.Context) error {
+				if err := configure(ctx); err != nil {
+					return err
+				}
+				// Show the draft slides with the DRAFT badge while writing
+				revealJS.IncludeDrafts = true
 				port := ctx.Int("port")
 				open := ctx.Bool("open")
 				server := revealjs.NewServer(port, revealJS)
@@ -117,35 +172,387 @@ func main() {
 		{
 			Name:  "export",
 			Usage: "Generate static slide files",
-			Flags: []cli.Flag{
+			Flags: append([]cli.Flag{
 				&cli.StringFlag{
 					Name:    "output",
 					Aliases: []string{"o"},
-					Value:   "build",
+					Usage:   "destination directory (default: 'buildDir' in config.yml)",
+				},
+				&cli.StringFlag{
+					Name:  "base-url",
+					Usage: "URL the slides are served from, overriding 'baseURL' in config.yml",
+				},
+				&cli.BoolFlag{
+					Name:  "prune",
+					Usage: "copy only the files referenced from the slides, overriding 'prune' in config.yml",
+				},
+				&cli.BoolFlag{
+					Name:  "fingerprint",
+					Usage: "rename the copied files with the hashes of their contents, overriding 'fingerprint' in config.yml",
+				},
+				&cli.BoolFlag{
+					Name:  "clean",
+					Usage: "remove the files in the destination directory not written by export, overriding 'clean' in config.yml",
 				},
 				&cli.StringFlag{
 					Name:    "format",
 					Aliases: []string{"f"},
 					Value:   "html",
+					Usage:   "output format (html|handout)",
 				},
-			},
+				&cli.IntFlag{
+					Name:  "slides-per-page",
+					Value: 3,
+					Usage: "number of slides on each handout page",
+				},
+				&cli.BoolFlag{
+					Name:  "force",
+					Usage: "rewrite all the files instead of reusing the files not changed since the previous export, and replace the destination not written by export",
+				},
+				&cli.BoolFlag{
+					Name:  "include-drafts",
+					Usage: "include the draft and hidden slides",
+				},
+				&cli.StringFlag{
+					Name:  "paper",
+					Value: revealjs.PaperSizeA4,
+					Usage: fmt.Sprintf("paper size of the handout (%s|%s)", revealjs.PaperSizeA4, revealjs.PaperSizeLetter),
+				},
+			}, configFlags...),
 			Action: func(ctx *cli.Context) error {
+				if err := configure(ctx); err != nil {
+					return err
+				}
+				// The flags take precedence over config.yml
+				overrides := make([]revealjs.ConfigOverride, 0)
+				if ctx.IsSet("base-url") {
+					overrides = append(overrides, revealjs.ConfigOverride{Key: "baseURL", Value: ctx.String("base-url")})
+				}
+				for _, name := range []string{"prune", "fingerprint", "clean"} {
+					if ctx.IsSet(name) {
+						overrides = append(overrides, revealjs.ConfigOverride{Key: name, Value: strconv.FormatBool(ctx.Bool(name))})
+					}
+				}
+				if len(overrides) > 0 {
+					if err := revealJS.OverrideConfig(overrides...); err != nil {
+						return err
+					}
+				}
+				if err := checkSlides(); err != nil {
+					return err
+				}
 				revealJS.EmbedHTML = true
 				revealJS.EmbedMarkdown = true
-				output, err := filepath.Abs(ctx.String("output"))
+				revealJS.IncludeDrafts = ctx.Bool("include-drafts")
+				revealJS.Force = ctx.Bool("force")
+				output := ctx.String("output")
+				if output == "" {
+					output = revealJS.BuildDirectory()
+				}
+				output, err := filepath.Abs(output)
+				if err != nil {
+					return err
+				}
+				switch format := ctx.String("format"); format {
+				case "html":
+					manifest, err := revealJS.BuildWithManifest(output)
+					if err != nil {
+						return err
+					}
+					fmt.Printf("%d files written to %s (%d unchanged)\n", manifest.Written(), output, len(manifest.Files)-manifest.Written())
+					if len(manifest.Pruned) > 0 {
+						fmt.Printf("%d unused files pruned (%s saved)\n", len(manifest.Pruned), formatSize(manifest.PrunedSize))
+					}
+					return nil
+				case "handout":
+					chromium, err := revealjs.FindChromium()
+					if err != nil {
+						return err
+					}
+					return revealJS.BuildHandout(output, &revealjs.HandoutOptions{
+						SlidesPerPage: ctx.Int("slides-per-page"),
+						PaperSize:     ctx.String("paper"),
+						Chromium:      chromium,
+					})
+				default:
+					return fmt.Errorf("unsupported format: %s", format)
+				}
+			},
+		},
+		{
+			Name:  "order",
+			Usage: "Print the slide files in the order they are shown",
+			Flags: configFlags,
+			Action: func(ctx *cli.Context) error {
+				if err := configure(ctx); err != nil {
+					return err
+				}
+				if err := checkSlides(); err != nil {
+					return err
+				}
+				files, err := revealJS.SlideFiles()
+				if err != nil {
+					return err
+				}
+				for i, file := range files {
+					fmt.Printf("%3d  %s\n", i+1, file)
+				}
+				return nil
+			},
+		},
+		{
+			Name:  "check",
+			Usage: "Check the slide files in 'slides' and the files and URLs referenced from the slides",
+			Description: "Prints the problems as 'file:line: message' and exits with status 1 if any problem is found.\n" +
+				"The external URLs are not fetched, but reported if their hosts are not allowed by --allow-host.\n" +
+				"With --overflow, the slides are laid out in the headless Chromium to find the content larger than the slide size.",
+			Flags: append([]cli.Flag{
+				&cli.StringSliceFlag{
+					Name:  "allow-host",
+					Usage: "host pattern of the allowed external URLs such as '*.example.com' (default: all hosts)",
+				},
+				&cli.BoolFlag{
+					Name:  "overflow",
+					Usage: "check every slide and fragment state for the content clipped by the slide size in the headless Chromium",
+				},
+				&cli.StringFlag{
+					Name:  "screenshot-dir",
+					Value: "overflow",
+					Usage: "directory to save the screenshots of the overflowing slides",
+				},
+			}, configFlags...),
+			Action: func(ctx *cli.Context) error {
+				if err := configure(ctx); err != nil {
+					return err
+				}
+				// Check the slides as exported
+				revealJS.IncludeDrafts = false
+				problems, err := revealJS.CheckLinks(&revealjs.LinkCheckOptions{
+					AllowedHosts: ctx.StringSlice("allow-host"),
+				})
 				if err != nil {
 					return err
 				}
-				format := ctx.String("format")
-				if format != "html" {
-					// currently only html is supported
+				if ctx.Bool("overflow") {
+					chromium, err := revealjs.FindChromium()
+					if err != nil {
+						return err
+					}
+					revealJS.EmbedHTML = true
+					revealJS.EmbedMarkdown = true
+					overflows, err := revealJS.CheckOverflow(&revealjs.OverflowCheckOptions{
+						Chromium:      chromium,
+						ScreenshotDir: ctx.String("screenshot-dir"),
+					})
+					if err != nil {
+						return err
+					}
+					problems = append(problems, overflows...)
+				}
+				for _, problem := range problems {
+					fmt.Println(problem)
+				}
+				if len(problems) > 0 {
+					return cli.Exit(fmt.Sprintf("%d problems found", len(problems)), 1)
+				}
+				return nil
+			},
+		},
+		{
+			Name:  "lint",
+			Usage: "Check the accessibility of the slides by the rules configured in 'lint' of config.yml",
+			Description: "Prints the problems with the slide files and the slide numbers,\n" +
+				"and exits with status 1 if any problem of the rules whose severity is 'error' is found.",
+			Flags: append([]cli.Flag{
+				&cli.StringFlag{
+					Name:    "format",
+					Aliases: []string{"f"},
+					Value:   "text",
+					Usage:   "output format (text|json)",
+				},
+			}, configFlags...),
+			Action: func(ctx *cli.Context) error {
+				if err := configure(ctx); err != nil {
+					return err
+				}
+				if err := checkSlides(); err != nil {
+					return err
+				}
+				// Check the slides as exported
+				revealJS.IncludeDrafts = false
+				problems, err := revealJS.Lint()
+				if err != nil {
+					return err
+				}
+				switch format := ctx.String("format"); format {
+				case "text":
+					for _, problem := range problems {
+						fmt.Println(problem)
+					}
+				case "json":
+					encoder := json.NewEncoder(os.Stdout)
+					encoder.SetIndent("", "  ")
+					encoder.SetEscapeHTML(false)
+					if err := encoder.Encode(problems); err != nil {
+						return err
+					}
+				default:
 					return fmt.Errorf("unsupported format: %s", format)
 				}
-				return revealJS.Build(output)
+				if n := revealjs.LintErrors(problems); n > 0 {
+					return cli.Exit(fmt.Sprintf("%d errors found", n), 1)
+				}
+				return nil
 			},
 		},
+		{
+			Name:  "outline",
+			Usage: "Print the outline of the slides",
+			Flags: append([]cli.Flag{
+				&cli.StringFlag{
+					Name:    "format",
+					Aliases: []string{"f"},
+					Value:   "tree",
+					Usage:   "output format (tree|json)",
+				},
+			}, configFlags...),
+			Action: func(ctx *cli.Context) error {
+				if err := configure(ctx); err != nil {
+					return err
+				}
+				if err := checkSlides(); err != nil {
+					return err
+				}
+				// Outline the slides as exported
+				revealJS.IncludeDrafts = false
+				deck, err := revealJS.Deck()
+				if err != nil {
+					return err
+				}
+				switch format := ctx.String("format"); format {
+				case "tree":
+					return deck.WriteTree(os.Stdout)
+				case "json":
+					encoder := json.NewEncoder(os.Stdout)
+					encoder.SetIndent("", "  ")
+					encoder.SetEscapeHTML(false)
+					return encoder.Encode(deck)
+				default:
+					return fmt.Errorf("unsupported format: %s", format)
+				}
+			},
+		},
+		{
+			Name:      "schema",
+			Usage:     "Print the JSON Schema of config.yml or the front matter of the markdown files",
+			ArgsUsage: "[config|front-matter]",
+			Description: "Save the schema and refer to it from the YAML language server of the editor, such as\n" +
+				"'# yaml-language-server: $schema=./revealcli.schema.json' at the top of config.yml.\n" +
+				"Add !append, !remove and !replace to 'yaml.customTags' of the language server to use the merge tags.",
+			Action: func(ctx *cli.Context) error {
+				var schema map[string]interface{}
+				switch target := ctx.Args().First(); target {
+				case "", "config":
+					schema = revealjs.ConfigJSONSchema()
+				case "front-matter":
+					schema = revealjs.FrontMatterJSONSchema()
+				default:
+					return fmt.Errorf("unsupported schema: %s", target)
+				}
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				encoder.SetEscapeHTML(false)
+				return encoder.Encode(schema)
+			},
+		},
+		{
+			Name:  "config",
+			Usage: "Print the effective config merged from the default config, config.yml and the front matters",
+			Subcommands: []*cli.Command{
+				{
+					Name:  "show",
+					Usage: "Print the effective config with the source of each value",
+					Flags: append([]cli.Flag{
+						&cli.StringFlag{
+							Name:    "format",
+							Aliases: []string{"f"},
+							Value:   "yaml",
+							Usage:   "output format (yaml|json)",
+						},
+					}, configFlags...),
+					Action: func(ctx *cli.Context) error {
+						if err := configure(ctx); err != nil {
+							return err
+						}
+						if err := revealJS.SlidesError(); err != nil {
+							fmt.Fprintf(os.Stderr, "warning: the front matter of the slide files not matched by 'slides' is not applied: %s\n", err)
+						}
+						return revealJS.WriteConfig(os.Stdout, ctx.String("format"))
+					},
+				},
+				{
+					Name:      "get",
+					Usage:     "Print the effective config value of the key such as 'revealjs.controls'",
+					ArgsUsage: "<key>",
+					Flags: append([]cli.Flag{
+						&cli.BoolFlag{
+							Name:  "source",
+							Usage: "print where the value is set instead of the value",
+						},
+					}, configFlags...),
+					Action: func(ctx *cli.Context) error {
+						if ctx.NArg() != 1 {
+							return errors.New("specify a config key")
+						}
+						if err := configure(ctx); err != nil {
+							return err
+						}
+						key := ctx.Args().First()
+						value, ok := revealJS.ConfigValue(key)
+						if !ok {
+							return fmt.Errorf("config '%s' is not set", key)
+						}
+						if ctx.Bool("source") {
+							fmt.Println(revealJS.ConfigSource(key))
+							return nil
+						}
+						switch value.(type) {
+						case map[string]interface{}, []interface{}:
+							b, err := json.Marshal(value)
+							if err != nil {
+								return err
+							}
+							fmt.Println(string(b))
+						default:
+							fmt.Println(value)
+						}
+						return nil
+					},
+				},
+			},
+		},
+	}
+	if err := app.Run(args); err != nil {
+		var exitErr cli.ExitCoder
+		if errors.As(err, &exitErr) {
+			fmt.Fprintln(os.Stderr, err)
+			return exitErr.ExitCode()
+		}
+		fmt.Fprintln(os.Stderr, "failed to execute: ", err)
+		return 1
+	}
+	return 0
+}
+
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
 	}
-	if err := app.Run(os.Args); err != nil {
-		fmt.Println("failed to execute: ", err)
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
 	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
 }
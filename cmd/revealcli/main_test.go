@@ -0,0 +1,44 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunExitStatus(t *testing.T) {
+	dataDir := t.TempDir()
+	writeFile(t, filepath.Join(dataDir, "slides.md"), "# Slide\n")
+	brokenDir := t.TempDir()
+	writeFile(t, filepath.Join(brokenDir, "slides.md"), "# Slide\n")
+	writeFile(t, filepath.Join(brokenDir, "config.yml"), "slides:\n  - slides.md\n  - missing.md\n")
+	nonBuildDir := t.TempDir()
+	writeFile(t, filepath.Join(nonBuildDir, "notes.txt"), "important")
+
+	for _, c := range []struct {
+		name     string
+		args     []string
+		expected int
+	}{
+		{"order", []string{"--dir", dataDir, "order"}, 0},
+		{"unknown profile", []string{"--dir", dataDir, "check", "--profile", "nope"}, 1},
+		{"broken slides in order", []string{"--dir", brokenDir, "order"}, 1},
+		{"broken slides in export", []string{"--dir", brokenDir, "export", "--output", filepath.Join(t.TempDir(), "build")}, 1},
+		{"broken slides in lint", []string{"--dir", brokenDir, "lint"}, 1},
+		{"problems found in check", []string{"--dir", brokenDir, "check"}, 1},
+		{"export to the directory not written by export", []string{"--dir", dataDir, "export", "--output", nonBuildDir}, 1},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			if status := run(append([]string{"revealcli"}, c.args...)); status != c.expected {
+				t.Errorf("expected exit status %d but %d", c.expected, status)
+			}
+		})
+	}
+}
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -27,6 +27,7 @@ var properties = map[string]Property{
 type (
 	Property interface {
 		ToString(v interface{}) (string, error)
+		jsonSchema() map[string]interface{}
 	}
 	StringProperty struct {
 		validValues []string
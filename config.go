@@ -1,22 +1,71 @@
 package revealjs
 
 import (
-	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	Slides          []string               `yaml:"slides"`
-	Title           string                 `yaml:"title"`
-	Theme           string                 `yaml:"theme"`
-	RevealJS        map[string]interface{} `yaml:"revealjs"`
-	InternalPlugins []interface{}          `yaml:"plugins"`
+	// Extends is the path of the base config file relative to this config file.
+	Extends string   `yaml:"extends,omitempty"`
+	Slides  []string `yaml:"slides,omitempty"`
+	Title   string   `yaml:"title,omitempty"`
+	// Lang is the language of the slides such as 'en', set to the lang attribute of the page.
+	Lang            string                 `yaml:"lang,omitempty"`
+	Theme           string                 `yaml:"theme,omitempty"`
+	RevealJS        map[string]interface{} `yaml:"revealjs,omitempty"`
+	InternalPlugins []interface{}          `yaml:"plugins,omitempty"`
+	Agenda          Agenda                 `yaml:"agenda,omitempty"`
+	Profiles        map[string]*Profile    `yaml:"profiles,omitempty"`
+	// BuildDir is the destination directory of the export relative to the data directory.
+	BuildDir string `yaml:"buildDir,omitempty"`
+	// BaseURL is the URL the exported slides are served from, such as https://example.com/talks/my-talk/.
+	BaseURL string `yaml:"baseURL,omitempty"`
+	// Prune skips copying the files not referenced from the slides, such as the themes and the plugins not used.
+	Prune *bool `yaml:"prune,omitempty"`
+	// Clean removes the files in the destination directory which are not written by the export, such as CNAME.
+	Clean *bool `yaml:"clean,omitempty"`
+	// Fingerprint renames the copied files with the hashes of their contents, and rewrites the references to them.
+	Fingerprint *bool `yaml:"fingerprint,omitempty"`
+	// Lint is the config of the accessibility rules checked by Lint.
+	Lint LintConfig `yaml:"lint,omitempty"`
+	// merge is the merge strategies given by the YAML tags, used by OverrideWith.
+	merge mergeStrategies
+	// sources is where the values are set, keyed by the path such as 'revealjs.controls'.
+	sources map[string]string
+}
+
+// Agenda is the config of the agenda slide generated from the outline of the deck.
+type Agenda struct {
+	Enabled *bool  `yaml:"enabled,omitempty"`
+	Title   string `yaml:"title,omitempty"`
+}
+
+// LintConfig is the config of the accessibility rules checked by Lint.
+type LintConfig struct {
+	// Rules is the severity of the rules keyed by the rule name such as 'image-alt'.
+	Rules map[string]LintSeverity `yaml:"rules,omitempty"`
+	// MinContrast is the minimum contrast ratio between the text color of the theme and the backgrounds of the slides.
+	MinContrast float64 `yaml:"minContrast,omitempty"`
+}
+
+// builtinPlugins is the scripts of the plugins bundled with reveal.js, keyed by the plugin name.
+var builtinPlugins = map[string]string{
+	"RevealHighlight": "plugin/highlight/highlight.js",
+	"RevealMarkdown":  "plugin/markdown/markdown.js",
+	"RevealSearch":    "plugin/search/search.js",
+	"RevealNotes":     "plugin/notes/notes.js",
+	"RevealMath":      "plugin/math/math.js",
+	"RevealZoom":      "plugin/zoom/zoom.js",
 }
 
 type Plugin struct {
@@ -29,63 +78,251 @@ func LoadConfigFile(reader io.Reader) (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	cfg, err := loadDefaultConfig()
+	if err != nil {
+		return nil, err
+	}
+	cfg.OverrideWith(loadedConfig)
+	return cfg, nil
+}
 
-	// Derive from default config
+func loadDefaultConfig() (*Config, error) {
 	defaultConfigFile, err := defaultConfigYAML()
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
-	cfg, err := doLoadConfigFile(defaultConfigFile)
+	c, err := doLoadConfigFile(defaultConfigFile)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
+	}
+	c.setSource(SourceDefault)
+	return c, nil
+}
+
+// LoadConfigFilePath loads the config file at path, and the base config files given by 'extends'.
+func LoadConfigFilePath(path string) (*Config, error) {
+	configs, err := loadExtendedConfigFiles(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := loadDefaultConfig()
+	if err != nil {
+		return nil, err
+	}
+	// Override in order from the base so that `!append` and `null` are applied to the default config
+	for _, c := range configs {
+		cfg.OverrideWith(c)
 	}
-	cfg.OverrideWith(loadedConfig)
 	return cfg, nil
 }
 
-func LoadConfigFromMarkdown(content string) (*Config, error) {
-	md := NewMarkdown(content)
-	if header, err := md.YAMLHeader(); err != nil {
+// loadExtendedConfigFiles loads the config file and the config files it extends, in order from the base.
+// chain is the files extending this file, used to detect the cyclic 'extends'.
+func loadExtendedConfigFiles(path string, chain []string) ([]*Config, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
 		return nil, err
-	} else {
-		b, err := yaml.Marshal(header)
-		if err != nil {
-			return nil, err
-		}
-		return doLoadConfigFile(bytes.NewReader(b))
 	}
+	chain = append(chain, path)
+	if slices.Contains(chain[:len(chain)-1], path) {
+		return nil, fmt.Errorf("cyclic 'extends' in config files: %s", strings.Join(chain, " -> "))
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	c, err := doLoadConfigFile(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config file %s: %w", path, err)
+	}
+	c.setSource(path)
+	if c.Extends == "" {
+		return []*Config{c}, nil
+	}
+
+	basePath := c.Extends
+	if !filepath.IsAbs(basePath) {
+		basePath = filepath.Join(filepath.Dir(path), basePath)
+	}
+	bases, err := loadExtendedConfigFiles(basePath, chain)
+	if err != nil {
+		return nil, err
+	}
+	return append(bases, c), nil
+}
+
+func LoadConfigFromMarkdown(content string) (*Config, error) {
+	md := NewMarkdown(content)
+	// Load the raw YAML header to keep the tags such as `!append`
+	return doLoadConfigFile(strings.NewReader(md.yamlHeaderText()))
 }
 
+// OverrideWith overrides the config with other.
+//
+// The maps such as `revealjs` are merged deeply, and the other values replace the values of this config.
+// The values in other can be tagged to change how they are merged:
+//
+//	plugins: !append [RevealMenu]        # appends the items to the list
+//	plugins: !remove [RevealMath]        # removes the items from the list, plugins are matched by name
+//	revealjs: !replace {controls: true}  # replaces the map or the list instead of merging
+//	theme: null                          # unsets the value
 func (c *Config) OverrideWith(other *Config) {
-	if other.Title != "" {
+	c.overrideSources(other)
+	s := other.merge
+	if len(other.Slides) > 0 || s["slides"] != mergeDefault {
+		c.Slides = mergeList(c.Slides, other.Slides, s["slides"])
+	}
+	if other.Title != "" || s["title"] == mergeUnset {
 		c.Title = other.Title
 	}
-	if other.Theme != "" {
+	if other.Lang != "" || s["lang"] == mergeUnset {
+		c.Lang = other.Lang
+	}
+	if other.Theme != "" || s["theme"] == mergeUnset {
 		c.Theme = other.Theme
 	}
-	if other.InternalPlugins != nil {
-		c.InternalPlugins = other.InternalPlugins
+	if other.InternalPlugins != nil || s["plugins"] != mergeDefault {
+		c.InternalPlugins = mergeList(c.InternalPlugins, other.InternalPlugins, s["plugins"])
 	}
-	if c.RevealJS == nil {
-		c.RevealJS = map[string]interface{}{}
+	if s["agenda"] == mergeUnset {
+		c.Agenda = Agenda{}
+	}
+	if other.Agenda.Enabled != nil || s["agenda.enabled"] == mergeUnset {
+		c.Agenda.Enabled = other.Agenda.Enabled
+	}
+	if other.Agenda.Title != "" || s["agenda.title"] == mergeUnset {
+		c.Agenda.Title = other.Agenda.Title
+	}
+
+	if other.BuildDir != "" || s["buildDir"] == mergeUnset {
+		c.BuildDir = other.BuildDir
+	}
+	if other.BaseURL != "" || s["baseURL"] == mergeUnset {
+		c.BaseURL = other.BaseURL
+	}
+	if other.Prune != nil || s["prune"] == mergeUnset {
+		c.Prune = other.Prune
+	}
+	if other.Clean != nil || s["clean"] == mergeUnset {
+		c.Clean = other.Clean
+	}
+	if other.Fingerprint != nil || s["fingerprint"] == mergeUnset {
+		c.Fingerprint = other.Fingerprint
+	}
+	if s["lint"] == mergeUnset {
+		c.Lint = LintConfig{}
+	}
+	switch s["lint.rules"] {
+	case mergeUnset:
+		c.Lint.Rules = nil
+	case mergeReplace:
+		c.Lint.Rules = other.Lint.Rules
+	default:
+		rules := make(map[string]LintSeverity, len(c.Lint.Rules)+len(other.Lint.Rules))
+		for name, severity := range c.Lint.Rules {
+			rules[name] = severity
+		}
+		for name, severity := range other.Lint.Rules {
+			if s["lint.rules."+name] == mergeUnset {
+				delete(rules, name)
+			} else {
+				rules[name] = severity
+			}
+		}
+		c.Lint.Rules = rules
+	}
+	if other.Lint.MinContrast != 0 || s["lint.minContrast"] == mergeUnset {
+		c.Lint.MinContrast = other.Lint.MinContrast
+	}
+
+	switch s["profiles"] {
+	case mergeUnset:
+		c.Profiles = nil
+	case mergeReplace:
+		c.Profiles = other.Profiles
+	default:
+		if len(other.Profiles) > 0 && c.Profiles == nil {
+			c.Profiles = map[string]*Profile{}
+		}
+		for name, profile := range other.Profiles {
+			if s["profiles."+name] == mergeUnset {
+				delete(c.Profiles, name)
+			} else {
+				c.Profiles[name] = profile
+			}
+		}
 	}
-	for k, v := range other.RevealJS {
-		c.RevealJS[k] = v
+
+	switch s["revealjs"] {
+	case mergeUnset:
+		c.RevealJS = map[string]interface{}{}
+	case mergeReplace:
+		c.RevealJS = s.mergeMap(nil, other.RevealJS, "revealjs")
+	default:
+		c.RevealJS = s.mergeMap(c.RevealJS, other.RevealJS, "revealjs")
 	}
 }
 
 func doLoadConfigFile(reader io.Reader) (*Config, error) {
-	var c Config
+	c := Config{merge: mergeStrategies{}, sources: map[string]string{}}
 	b, err := io.ReadAll(reader)
 	if err != nil {
 		return nil, err
 	}
-	if err := yaml.Unmarshal(b, &c); err != nil {
+	var node yaml.Node
+	if err := yaml.Unmarshal(b, &node); err != nil {
 		return nil, err
 	}
+	if node.Kind == 0 {
+		// empty file
+		return &c, nil
+	}
+	if err := c.merge.collect(&node, ""); err != nil {
+		return nil, err
+	}
+	for _, key := range configKeys(&node, "") {
+		c.sources[key] = ""
+	}
+	if err := node.Decode(&c); err != nil {
+		return nil, err
+	}
+	for name, profile := range c.Profiles {
+		if profile == nil {
+			continue
+		}
+		prefix := "profiles." + name
+		profile.merge = c.merge.sub(prefix)
+		profile.sources = map[string]string{}
+		for key := range c.sources {
+			if rest, ok := strings.CutPrefix(key, prefix+"."); ok {
+				profile.sources[rest] = ""
+			}
+		}
+	}
 	return &c, nil
 }
 
+func (a Agenda) IsEnabled() bool {
+	return a.Enabled != nil && *a.Enabled
+}
+
+// PruneAssets returns true if the files not referenced from the slides are not copied to the export.
+func (c *Config) PruneAssets() bool {
+	return c.Prune != nil && *c.Prune
+}
+
+// FingerprintAssets returns true if the copied files are renamed with the hashes of their contents.
+func (c *Config) FingerprintAssets() bool {
+	return c.Fingerprint != nil && *c.Fingerprint
+}
+
+// CleanBuildDir returns true if the files in the destination directory not written by the export are removed.
+func (c *Config) CleanBuildDir() bool {
+	return c.Clean != nil && *c.Clean
+}
+
 func (c *Config) Plugins() []Plugin {
 	plugins := []Plugin{}
 	for _, v := range c.InternalPlugins {
@@ -101,22 +338,11 @@ func (c *Config) Plugins() []Plugin {
 			json.Unmarshal(b, &plugin)
 		}
 		if plugin.Src == "" {
-			switch plugin.Name {
-			case "RevealHighlight":
-				plugin.Src = "plugin/highlight/highlight.js"
-			case "RevealMarkdown":
-				plugin.Src = "plugin/markdown/markdown.js"
-			case "RevealSearch":
-				plugin.Src = "plugin/search/search.js"
-			case "RevealNotes":
-				plugin.Src = "plugin/notes/notes.js"
-			case "RevealMath":
-				plugin.Src = "plugin/math/math.js"
-			case "RevealZoom":
-				plugin.Src = "plugin/zoom/zoom.js"
-			default:
+			src, ok := builtinPlugins[plugin.Name]
+			if !ok {
 				log.Fatalf("plugin %s is not supported", plugin.Name)
 			}
+			plugin.Src = src
 		}
 		plugins = append(plugins, plugin)
 	}
@@ -0,0 +1,303 @@
+package revealjs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func loadConfig(t *testing.T, s string) *Config {
+	t.Helper()
+	c, err := doLoadConfigFile(strings.NewReader(s))
+	if err != nil {
+		t.Fatalf("failed to load config: %s", err)
+	}
+	return c
+}
+
+func TestOverrideWith(t *testing.T) {
+	base := `
+title: Base
+theme: black
+slides: [a.md, b.md]
+plugins:
+  - RevealMarkdown
+  - {name: RevealMath, src: plugin/math/math.js}
+agenda:
+  enabled: true
+  title: Agenda
+lint:
+  rules: {image-alt: error, heading-order: "off", contrast: warning}
+  minContrast: 3
+profiles:
+  public: {title: Public}
+  short: {title: Short}
+revealjs:
+  controls: true
+  autoAnimateStyles: [opacity, color]
+  menu:
+    side: left
+    width: normal
+`
+	tests := []struct {
+		name  string
+		other string
+		check func(t *testing.T, c *Config)
+	}{
+		{
+			name:  "empty config keeps the values",
+			other: ``,
+			check: func(t *testing.T, c *Config) {
+				assertEqual(t, c.Title, "Base")
+				assertEqual(t, c.Slides, []string{"a.md", "b.md"})
+				assertEqual(t, len(c.InternalPlugins), 2)
+				assertEqual(t, c.RevealJS["controls"], true)
+			},
+		},
+		{
+			name:  "scalars are replaced",
+			other: `{title: Other, revealjs: {controls: false}}`,
+			check: func(t *testing.T, c *Config) {
+				assertEqual(t, c.Title, "Other")
+				assertEqual(t, c.Theme, "black")
+				assertEqual(t, c.RevealJS["controls"], false)
+			},
+		},
+		{
+			name:  "nested maps are merged deeply",
+			other: `{revealjs: {menu: {side: right}}}`,
+			check: func(t *testing.T, c *Config) {
+				assertEqual(t, c.RevealJS["menu"], map[string]interface{}{"side": "right", "width": "normal"})
+				assertEqual(t, c.RevealJS["controls"], true)
+			},
+		},
+		{
+			name:  "lists are replaced by default",
+			other: `{plugins: [RevealZoom], slides: [c.md], revealjs: {autoAnimateStyles: [padding]}}`,
+			check: func(t *testing.T, c *Config) {
+				assertEqual(t, c.InternalPlugins, []interface{}{"RevealZoom"})
+				assertEqual(t, c.Slides, []string{"c.md"})
+				assertEqual(t, c.RevealJS["autoAnimateStyles"], []interface{}{"padding"})
+			},
+		},
+		{
+			name:  "!append appends the items",
+			other: `{plugins: !append [RevealZoom], slides: !append [c.md], revealjs: {autoAnimateStyles: !append [padding]}}`,
+			check: func(t *testing.T, c *Config) {
+				assertEqual(t, c.InternalPlugins, []interface{}{
+					"RevealMarkdown",
+					map[string]interface{}{"name": "RevealMath", "src": "plugin/math/math.js"},
+					"RevealZoom",
+				})
+				assertEqual(t, c.Slides, []string{"a.md", "b.md", "c.md"})
+				assertEqual(t, c.RevealJS["autoAnimateStyles"], []interface{}{"opacity", "color", "padding"})
+			},
+		},
+		{
+			name:  "!remove removes the items",
+			other: `{plugins: !remove [RevealMath], slides: !remove [a.md], revealjs: {autoAnimateStyles: !remove [color]}}`,
+			check: func(t *testing.T, c *Config) {
+				assertEqual(t, c.InternalPlugins, []interface{}{"RevealMarkdown"})
+				assertEqual(t, c.Slides, []string{"b.md"})
+				assertEqual(t, c.RevealJS["autoAnimateStyles"], []interface{}{"opacity"})
+			},
+		},
+		{
+			name:  "!replace replaces the map",
+			other: `{revealjs: {menu: !replace {side: right}}}`,
+			check: func(t *testing.T, c *Config) {
+				assertEqual(t, c.RevealJS["menu"], map[string]interface{}{"side": "right"})
+				assertEqual(t, c.RevealJS["controls"], true)
+			},
+		},
+		{
+			name:  "!replace replaces the whole revealjs config",
+			other: `{revealjs: !replace {controls: false}}`,
+			check: func(t *testing.T, c *Config) {
+				assertEqual(t, c.RevealJS, map[string]interface{}{"controls": false})
+			},
+		},
+		{
+			name:  "null unsets the values",
+			other: `{theme: null, slides: null, agenda: {enabled: null}, profiles: {short: null}, revealjs: {controls: null, menu: {width: null}}}`,
+			check: func(t *testing.T, c *Config) {
+				assertEqual(t, c.Title, "Base")
+				assertEqual(t, c.Theme, "")
+				assertEqual(t, len(c.Slides), 0)
+				assertEqual(t, c.Agenda.Enabled == nil, true)
+				assertEqual(t, c.Agenda.Title, "Agenda")
+				assertEqual(t, len(c.Profiles), 1)
+				assertEqual(t, c.Profiles["public"].Title, "Public")
+				_, ok := c.RevealJS["controls"]
+				assertEqual(t, ok, false)
+				assertEqual(t, c.RevealJS["menu"], map[string]interface{}{"side": "left"})
+			},
+		},
+		{
+			name:  "lint rules are merged",
+			other: `{lint: {rules: {image-alt: warning, contrast: null}}}`,
+			check: func(t *testing.T, c *Config) {
+				assertEqual(t, c.Lint.Rules, map[string]LintSeverity{"image-alt": LintSeverityWarning, "heading-order": LintSeverityOff})
+				assertEqual(t, c.Lint.MinContrast, 3.0)
+			},
+		},
+		{
+			name:  "null unsets the lists and the maps",
+			other: `{plugins: null, agenda: null, revealjs: null}`,
+			check: func(t *testing.T, c *Config) {
+				assertEqual(t, len(c.InternalPlugins), 0)
+				assertEqual(t, c.Agenda, Agenda{})
+				assertEqual(t, len(c.RevealJS), 0)
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := loadConfig(t, base)
+			other := loadConfig(t, test.other)
+			c.OverrideWith(other)
+			test.check(t, c)
+		})
+	}
+}
+
+func TestOverrideWithDoesNotModifyOther(t *testing.T) {
+	profile := loadConfig(t, `revealjs: {menu: {side: right}, autoAnimateStyles: !append [padding]}`)
+	for i := 0; i < 2; i++ {
+		c := loadConfig(t, `revealjs: {menu: {side: left}, autoAnimateStyles: [opacity]}`)
+		c.OverrideWith(profile)
+		c.OverrideWith(loadConfig(t, `revealjs: {menu: {width: wide}}`))
+		assertEqual(t, c.RevealJS["menu"], map[string]interface{}{"side": "right", "width": "wide"})
+		assertEqual(t, c.RevealJS["autoAnimateStyles"], []interface{}{"opacity", "padding"})
+	}
+	assertEqual(t, profile.RevealJS["menu"], map[string]interface{}{"side": "right"})
+}
+
+func TestOverrideWithProfile(t *testing.T) {
+	c := loadConfig(t, `
+plugins: [RevealMarkdown, RevealMath]
+profiles:
+  print:
+    plugins: !remove [RevealMath]
+`)
+	c.OverrideWith(&c.Profiles["print"].Config)
+	assertEqual(t, c.InternalPlugins, []interface{}{"RevealMarkdown"})
+}
+
+func TestLoadConfigUnknownTag(t *testing.T) {
+	for _, s := range []string{`plugins: !prepend [RevealZoom]`, `title: !append Title`} {
+		if _, err := doLoadConfigFile(strings.NewReader(s)); err == nil {
+			t.Errorf("expected error for %q", s)
+		}
+	}
+}
+
+func TestLoadConfigFromMarkdownKeepsTags(t *testing.T) {
+	c, err := LoadConfigFromMarkdown("---\nplugins: !append [RevealZoom]\ntheme: null\n---\n# Slide\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, c.merge["plugins"], mergeAppend)
+	assertEqual(t, c.merge["theme"], mergeUnset)
+}
+
+func TestLoadConfigFilePathMergesFromDefault(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base.yml"), "plugins: !remove [RevealMath]\nrevealjs:\n  keyboardCondition: focused\n")
+	writeFile(t, filepath.Join(dir, "config.yml"), "extends: base.yml\nplugins: !append [RevealMenu]\nrevealjs:\n  autoAnimateStyles: !append [outline]\n  keyboardCondition: null\n")
+
+	defaults, err := loadDefaultConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadConfigFilePath(filepath.Join(dir, "config.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plugins := mergeList(defaults.InternalPlugins, []interface{}{"RevealMath"}, mergeRemove)
+	assertEqual(t, c.InternalPlugins, append(plugins, "RevealMenu"))
+	styles := defaults.RevealJS["autoAnimateStyles"].([]interface{})
+	assertEqual(t, c.RevealJS["autoAnimateStyles"], append(styles[:len(styles):len(styles)], "outline"))
+	_, ok := c.RevealJS["keyboardCondition"]
+	assertEqual(t, ok, false)
+}
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertEqual(t *testing.T, actual, expected interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %#v, but got %#v", expected, actual)
+	}
+}
+
+func TestConfigSource(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base.yml"), "theme: white\nrevealjs:\n  menu: {side: left, width: wide}\nprofiles:\n  public: {title: Public}\n")
+	writeFile(t, filepath.Join(dir, "config.yml"), "extends: base.yml\ntitle: Talk\nrevealjs:\n  menu: {side: right}\n")
+	c, err := LoadConfigFilePath(filepath.Join(dir, "config.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	frontMatter, err := LoadConfigFromMarkdown("---\nrevealjs:\n  controls: false\n---\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	frontMatter.setSource("slides.md")
+	c.OverrideWith(frontMatter)
+
+	assertEqual(t, c.Source("title"), filepath.Join(dir, "config.yml"))
+	assertEqual(t, c.Source("theme"), filepath.Join(dir, "base.yml"))
+	assertEqual(t, c.Source("plugins"), SourceDefault)
+	assertEqual(t, c.Source("revealjs.menu.side"), filepath.Join(dir, "config.yml"))
+	assertEqual(t, c.Source("revealjs.menu.width"), filepath.Join(dir, "base.yml"))
+	assertEqual(t, c.Source("revealjs.controls"), "slides.md")
+	assertEqual(t, c.Source("revealjs.transition"), SourceDefault)
+	assertEqual(t, c.Source("unknown"), "")
+
+	c.OverrideWith(&c.Profiles["public"].Config)
+	assertEqual(t, c.Source("title"), filepath.Join(dir, "base.yml")+" (profile 'public')")
+
+	c.OverrideWith(loadConfig(t, "revealjs: {menu: !replace {side: top}}"))
+	assertEqual(t, c.Source("revealjs.menu.width"), "")
+
+	value, ok := c.Get("revealjs.menu")
+	assertEqual(t, ok, true)
+	assertEqual(t, value, map[string]interface{}{"side": "top"})
+	_, ok = c.Get("revealjs.menu.width")
+	assertEqual(t, ok, false)
+}
+
+func TestReloadConfigWithBrokenSlides(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "config.yml"), "slides:\n  - intro.md\n  - missing.md\n  - '[broken'\n")
+	writeFile(t, filepath.Join(dir, "intro.md"), "---\ntitle: From front matter\n---\n# Intro\n")
+	r, err := NewRevealJS(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The front matter of the files matching the valid entries is applied
+	assertEqual(t, r.config.Title, "From front matter")
+	err = r.SlidesError()
+	var patternErr *SlidePatternError
+	if !errors.As(err, &patternErr) {
+		t.Fatalf("expected SlidePatternError but %v", err)
+	}
+	assertEqual(t, err.Error(), "no slide files match 'missing.md' in slides\ninvalid pattern '[broken' in slides: syntax error in pattern")
+	// The deck is made of the valid entries
+	deck, err := r.Deck()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, len(deck.Sources), 1)
+	assertEqual(t, deck.Sources[0].File, "intro.md")
+}
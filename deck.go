@@ -0,0 +1,567 @@
+package revealjs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	markdownSeparator         = `^\r?\n---\r?\n$`
+	markdownVerticalSeparator = `^\r?\n~~~\r?\n$`
+	markdownNotesSeparator    = `^\s*notes?:`
+	// markdownStackSeparator is the separator of the markdown in a vertical stack.
+	// Both separators split vertical slides because a stack can't be nested.
+	markdownStackSeparator = `^\r?\n(?:---|~~~)\r?\n$`
+)
+
+var (
+	markdownSeparatorRegexp         = regexp.MustCompile("(?m)" + markdownSeparator)
+	markdownVerticalSeparatorRegexp = regexp.MustCompile("(?m)" + markdownVerticalSeparator)
+	markdownNotesSeparatorRegexp    = regexp.MustCompile("(?mi)" + markdownNotesSeparator)
+	markdownHeadingRegexp           = regexp.MustCompile(`^#{1,6}\s+(.*?)(\s+#+)?\s*$`)
+	markdownSetextUnderlineRegexp   = regexp.MustCompile(`^(=+|-+)\s*$`)
+	markdownSlideAttributesRegexp   = regexp.MustCompile(`<!--\s*\.slide:\s*(.*?)\s*-->`)
+	htmlAttributeRegexp             = regexp.MustCompile(`([^\s=]+)="([^"]*)"`)
+)
+
+type SlideKind string
+
+const (
+	SlideKindHTML     SlideKind = "html"
+	SlideKindMarkdown SlideKind = "markdown"
+	// SlideKindStack is a directory in 'slides' whose files are shown as a vertical stack.
+	SlideKindStack SlideKind = "stack"
+)
+
+type (
+	// Deck is the model of the presentation.
+	Deck struct {
+		// Sources is the slide sources in the order reveal.js shows them.
+		Sources []*SlideSource `json:"sources"`
+	}
+
+	// SlideSource is a slide file, a directory of the vertical stack or a generated source such as the agenda.
+	// Each source is rendered to the top level <section> tags of index.html.
+	SlideSource struct {
+		// File is the slide file or the directory relative to the data directory, or empty for generated sources.
+		File string    `json:"file"`
+		Kind SlideKind `json:"kind"`
+		// FrontMatter is the YAML header of the markdown file.
+		FrontMatter map[string]interface{} `json:"frontMatter,omitempty"`
+		// Content is the content of the file without the front matter.
+		Content string `json:"-"`
+		// Slides is the horizontal slides of the source.
+		// The slides of the files in a stack belong to the stack.
+		Slides []*Slide `json:"slides,omitempty"`
+		// Children is the files in the stack.
+		Children []*SlideSource `json:"children,omitempty"`
+		// Draft is true if the file is marked as draft by 'draft: true' in the front matter.
+		Draft bool `json:"draft,omitempty"`
+		// Tags is the tags of the all slides in the file given by 'tags' in the front matter.
+		Tags []string `json:"tags,omitempty"`
+	}
+
+	// Slide is a single slide of the deck.
+	Slide struct {
+		// H and V are the horizontal and vertical index as used in the reveal.js URL hash (#/h/v).
+		H     int    `json:"h"`
+		V     int    `json:"v"`
+		ID    string `json:"id,omitempty"`
+		Title string `json:"title,omitempty"`
+		// Attributes is the attributes of the <section> tag, or given by <!-- .slide: --> comment in markdown.
+		Attributes map[string]string `json:"attributes,omitempty"`
+		// Content is the inner HTML or the markdown of the slide without the notes.
+		Content string `json:"content"`
+		Notes   string `json:"notes,omitempty"`
+		// Vertical is the slides stacked below this slide.
+		Vertical []*Slide `json:"vertical,omitempty"`
+		// Draft is true if the slide is hidden by data-visibility="hidden" or its file is a draft.
+		Draft bool `json:"draft,omitempty"`
+		// Tags is given by data-tags attribute such as data-tags="internal,short", and by the tags of its file.
+		Tags []string `json:"tags,omitempty"`
+
+		source *SlideSource
+	}
+)
+
+// Deck loads the model of the presentation.
+func (r *RevealJS) Deck() (*Deck, error) {
+	files, err := r.collectSlideSourceFiles()
+	if err != nil {
+		return nil, err
+	}
+	deck := &Deck{Sources: make([]*SlideSource, 0)}
+	var stack *SlideSource
+	for _, file := range files {
+		source, err := r.loadSlideSource(file)
+		if err != nil {
+			log.Printf("failed to load slide file %s: %s", file, err)
+			continue
+		}
+		if !r.IncludeDrafts {
+			if err := source.removeSlides(func(slide *Slide) bool { return slide.Draft }); err != nil {
+				return nil, fmt.Errorf("failed to remove draft slides in %s: %w", file, err)
+			}
+		} else {
+			source.markDrafts()
+		}
+		if profile := r.profile(); profile != nil && profile.filtersSlides() {
+			if err := source.removeSlides(func(slide *Slide) bool { return !profile.selects(slide) }); err != nil {
+				return nil, fmt.Errorf("failed to select slides of profile in %s: %w", file, err)
+			}
+		}
+		if len(source.Slides) == 0 {
+			continue
+		}
+		dir := stackOf(file)
+		if dir == "" {
+			stack = nil
+			deck.Sources = append(deck.Sources, source)
+			continue
+		}
+		if stack == nil || stack.File != dir {
+			stack = &SlideSource{File: dir, Kind: SlideKindStack}
+			deck.Sources = append(deck.Sources, stack)
+		}
+		stack.addChild(source)
+	}
+	if r.config.Agenda.IsEnabled() {
+		deck.insertAgenda(r.config.Agenda.Title)
+	}
+	deck.updateIndices()
+	return deck, nil
+}
+
+func (r *RevealJS) loadSlideSource(file string) (*SlideSource, error) {
+	b, err := os.ReadFile(filepath.Join(r.dataDirectory, file))
+	if err != nil {
+		return nil, err
+	}
+	content := string(b)
+
+	if IsHTML(file) {
+		slides, err := parseHTMLSlides(content)
+		if err != nil {
+			return nil, err
+		}
+		return newSlideSource(file, SlideKindHTML, nil, content, slides), nil
+	} else if IsMarkdown(file) {
+		md := NewMarkdown(content)
+		frontMatter, err := md.YAMLHeader()
+		if err != nil {
+			return nil, err
+		}
+		content = md.WithoutYAMLHeader()
+		return newSlideSource(file, SlideKindMarkdown, frontMatter, content, parseMarkdownSlides(content)), nil
+	}
+	return nil, fmt.Errorf("unsupported slide file: %s", file)
+}
+
+func newSlideSource(file string, kind SlideKind, frontMatter map[string]interface{}, content string, slides []*Slide) *SlideSource {
+	source := &SlideSource{
+		File:        file,
+		Kind:        kind,
+		FrontMatter: frontMatter,
+		Content:     content,
+		Slides:      slides,
+	}
+	header := parseSlideFrontMatter(frontMatter)
+	source.Draft = header.Draft
+	source.Tags = header.Tags
+	for _, slide := range source.AllSlides() {
+		source.inherit(slide)
+	}
+	return source
+}
+
+// inherit applies the draft flag and the tags of the file to the slide.
+func (s *SlideSource) inherit(slide *Slide) {
+	slide.source = s
+	slide.Draft = slide.Draft || s.Draft
+	slide.Tags = appendTags(slide.Tags, s.Tags...)
+}
+
+// addChild adds the file to the stack and stacks its slides vertically.
+func (s *SlideSource) addChild(child *SlideSource) {
+	s.Children = append(s.Children, child)
+	slides := child.AllSlides()
+	child.Slides = nil
+	for _, slide := range slides {
+		slide.Vertical = nil
+	}
+	if len(s.Slides) == 0 {
+		if len(slides) == 0 {
+			return
+		}
+		s.Slides = []*Slide{slides[0]}
+		slides = slides[1:]
+	}
+	s.Slides[0].Vertical = append(s.Slides[0].Vertical, slides...)
+}
+
+// updateIndices assigns the reveal.js indices to the slides.
+func (d *Deck) updateIndices() {
+	for h, slide := range d.Slides() {
+		slide.H = h
+		slide.V = 0
+		for v, vertical := range slide.Vertical {
+			vertical.H = h
+			vertical.V = v + 1
+		}
+	}
+}
+
+// sourceOf returns the source of the slide file in the deck, or nil if none of its slides is shown.
+func (d *Deck) sourceOf(file string) *SlideSource {
+	for _, source := range d.Sources {
+		if source.File == file && source.Kind != SlideKindStack {
+			return source
+		}
+		for _, child := range source.Children {
+			if child.File == file {
+				return child
+			}
+		}
+	}
+	return nil
+}
+
+// Slides returns the horizontal slides of the deck.
+func (d *Deck) Slides() []*Slide {
+	slides := make([]*Slide, 0)
+	for _, source := range d.Sources {
+		slides = append(slides, source.Slides...)
+	}
+	return slides
+}
+
+// AllSlides returns all the slides of the deck including the vertical slides in the order reveal.js shows them.
+func (d *Deck) AllSlides() []*Slide {
+	slides := make([]*Slide, 0)
+	for _, source := range d.Sources {
+		slides = append(slides, source.AllSlides()...)
+	}
+	return slides
+}
+
+// AllSlides returns all the slides of the source including the vertical slides.
+func (s *SlideSource) AllSlides() []*Slide {
+	slides := make([]*Slide, 0)
+	for _, slide := range s.Slides {
+		slides = append(slides, slide)
+		slides = append(slides, slide.Vertical...)
+	}
+	return slides
+}
+
+// Source returns the source that the slide belongs to.
+func (s *Slide) Source() *SlideSource {
+	return s.source
+}
+
+// Number returns the 1-based slide number such as "3" or "3.2".
+func (s *Slide) Number() string {
+	if s.V == 0 {
+		return fmt.Sprint(s.H + 1)
+	}
+	return fmt.Sprintf("%d.%d", s.H+1, s.V+1)
+}
+
+// Link returns the URL hash to navigate to the slide.
+func (s *Slide) Link() string {
+	if s.ID != "" {
+		return "#/" + s.ID
+	}
+	if s.V == 0 {
+		return fmt.Sprintf("#/%d", s.H)
+	}
+	return fmt.Sprintf("#/%d/%d", s.H, s.V)
+}
+
+// WriteTree writes the deck as a human readable tree grouped by slide file.
+func (d *Deck) WriteTree(w io.Writer) error {
+	for _, source := range d.Sources {
+		name := source.File
+		if name == "" {
+			name = "(generated)"
+		}
+		if _, err := fmt.Fprintln(w, name); err != nil {
+			return err
+		}
+		var file *SlideSource
+		for _, slide := range source.AllSlides() {
+			if source.Kind == SlideKindStack && slide.Source() != file {
+				// Files in the stack
+				file = slide.Source()
+				if _, err := fmt.Fprintf(w, "  %s\n", file.File); err != nil {
+					return err
+				}
+			}
+			indent := "  "
+			if slide.V > 0 || source.Kind == SlideKindStack {
+				indent = "    "
+			}
+			line := fmt.Sprintf("%s%s %s", indent, slide.Number(), slide.Title)
+			if slide.ID != "" {
+				line += fmt.Sprintf(" #%s", slide.ID)
+			}
+			if slide.Draft {
+				line += " [draft]"
+			}
+			if _, err := fmt.Fprintln(w, strings.TrimRight(line, " ")); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// parseHTMLSlides parses the top level <section> tags of the HTML slide file.
+func parseHTMLSlides(content string) ([]*Slide, error) {
+	nodes, err := html.ParseFragment(strings.NewReader(content), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		return nil, err
+	}
+	slides := make([]*Slide, 0)
+	for _, node := range nodes {
+		if node.Type != html.ElementNode || node.DataAtom != atom.Section {
+			continue
+		}
+		var stack []*Slide
+		for c := node.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && c.DataAtom == atom.Section {
+				stack = append(stack, newStackedHTMLSlide(node, c))
+			}
+		}
+		if len(stack) == 0 {
+			slides = append(slides, newHTMLSlide(node))
+		} else {
+			stack[0].Vertical = stack[1:]
+			slides = append(slides, stack[0])
+		}
+	}
+	return slides, nil
+}
+
+func newHTMLSlide(node *html.Node) *Slide {
+	attributes := make(map[string]string)
+	for _, attr := range node.Attr {
+		attributes[attr.Key] = attr.Val
+	}
+	notes := htmlNotes(node)
+	if n, ok := attributes["data-notes"]; ok {
+		notes = n
+	}
+	return &Slide{
+		ID:         attributes["id"],
+		Title:      htmlTitle(node),
+		Attributes: attributes,
+		Content:    innerHTML(node),
+		Notes:      notes,
+		Draft:      attributes["data-visibility"] == "hidden",
+		Tags:       splitTags(attributes["data-tags"]),
+	}
+}
+
+// newStackedHTMLSlide returns the slide in the vertical stack, which inherits the draft flag and the tags of the stack.
+func newStackedHTMLSlide(stack *html.Node, node *html.Node) *Slide {
+	parent := newHTMLSlide(stack)
+	slide := newHTMLSlide(node)
+	slide.Draft = slide.Draft || parent.Draft
+	slide.Tags = appendTags(slide.Tags, parent.Tags...)
+	return slide
+}
+
+// parseMarkdownSlides splits the markdown the same way as the reveal.js markdown plugin does.
+func parseMarkdownSlides(content string) []*Slide {
+	slides := make([]*Slide, 0)
+	for _, horizontal := range markdownSeparatorRegexp.Split(content, -1) {
+		var stack []*Slide
+		for _, vertical := range markdownVerticalSeparatorRegexp.Split(horizontal, -1) {
+			stack = append(stack, newMarkdownSlide(vertical))
+		}
+		stack[0].Vertical = stack[1:]
+		slides = append(slides, stack[0])
+	}
+	return slides
+}
+
+func newMarkdownSlide(content string) *Slide {
+	slide := &Slide{Attributes: make(map[string]string)}
+	if parts := markdownNotesSeparatorRegexp.Split(content, -1); len(parts) == 2 {
+		content = parts[0]
+		slide.Notes = strings.TrimSpace(parts[1])
+	}
+	slide.Content = strings.TrimSpace(content)
+	if matches := markdownSlideAttributesRegexp.FindStringSubmatch(content); matches != nil {
+		for _, attr := range htmlAttributeRegexp.FindAllStringSubmatch(matches[1], -1) {
+			slide.Attributes[attr[1]] = attr[2]
+		}
+	}
+	slide.ID = slide.Attributes["id"]
+	slide.Draft = slide.Attributes["data-visibility"] == "hidden"
+	slide.Tags = splitTags(slide.Attributes["data-tags"])
+
+	inCodeBlock := false
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock {
+			continue
+		}
+		if matches := markdownHeadingRegexp.FindStringSubmatch(strings.TrimSpace(line)); matches != nil {
+			slide.Title = matches[1]
+			break
+		}
+	}
+	return slide
+}
+
+// htmlTitle returns the text of the first heading in the slide.
+func htmlTitle(node *html.Node) string {
+	for c := node.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode || c.DataAtom == atom.Section {
+			continue
+		}
+		switch c.DataAtom {
+		case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+			return strings.Join(strings.Fields(textContent(c)), " ")
+		}
+		if title := htmlTitle(c); title != "" {
+			return title
+		}
+	}
+	return ""
+}
+
+// htmlNotes returns the speaker notes of the slide given by <aside class="notes">.
+func htmlNotes(node *html.Node) string {
+	var notes []string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			if c.DataAtom == atom.Section {
+				// Notes of the nested slides
+				continue
+			}
+			if class, _ := htmlAttr(c, "class"); c.DataAtom == atom.Aside && hasClass(class, "notes") {
+				notes = append(notes, strings.TrimSpace(textContent(c)))
+				continue
+			}
+			walk(c)
+		}
+	}
+	walk(node)
+	return strings.Join(notes, "\n")
+}
+
+// SlideFrontMatter is the front matter of the markdown slide file, in addition to the config.
+type SlideFrontMatter struct {
+	// Order is the order of the file among the files in the same directory.
+	Order int `yaml:"order"`
+	// Draft excludes the slides in the file from the export.
+	Draft bool `yaml:"draft"`
+	// Tags is the tags of the slides in the file, such as [internal, short] or 'internal, short'.
+	Tags SlideTags `yaml:"tags"`
+}
+
+// SlideTags is the list of the tags, or the tags separated by commas or spaces.
+type SlideTags []string
+
+func (t *SlideTags) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*t = splitTags(node.Value)
+		return nil
+	}
+	var tags []string
+	if err := node.Decode(&tags); err != nil {
+		return err
+	}
+	*t = tags
+	return nil
+}
+
+// parseSlideFrontMatter parses the front matter, ignoring the invalid values.
+func parseSlideFrontMatter(frontMatter map[string]interface{}) *SlideFrontMatter {
+	var header SlideFrontMatter
+	if b, err := yaml.Marshal(frontMatter); err == nil {
+		yaml.Unmarshal(b, &header)
+	}
+	return &header
+}
+
+// splitTags splits the tags separated by commas or spaces.
+func splitTags(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return r == ',' || unicode.IsSpace(r) })
+}
+
+func appendTags(tags []string, others ...string) []string {
+	for _, tag := range others {
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func htmlAttr(node *html.Node, key string) (string, bool) {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return attr.Val, true
+		}
+	}
+	return "", false
+}
+
+func hasClass(classAttr string, class string) bool {
+	for _, c := range strings.Fields(classAttr) {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+func innerHTML(node *html.Node) string {
+	var buf bytes.Buffer
+	for c := node.FirstChild; c != nil; c = c.NextSibling {
+		html.Render(&buf, c)
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+func textContent(node *html.Node) string {
+	var sb strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(node)
+	return sb.String()
+}
@@ -0,0 +1,50 @@
+package revealjs
+
+import (
+	"regexp"
+)
+
+// draftAttribute marks the slide to be shown with the DRAFT badge.
+// reveal.js removes the slides with data-visibility="hidden", so the hidden slides are marked with this attribute instead.
+const draftAttribute = `data-draft="true"`
+
+var (
+	hiddenAttributeRegexp = regexp.MustCompile(`data-visibility=["']hidden["']`)
+	sectionStartTagRegexp = regexp.MustCompile(`<section\b[^>]*>`)
+)
+
+// markDrafts marks the draft slides to be shown with the DRAFT badge.
+func (s *SlideSource) markDrafts() {
+	switch s.Kind {
+	case SlideKindHTML:
+		s.Content = sectionStartTagRegexp.ReplaceAllStringFunc(s.Content, func(tag string) string {
+			return hiddenAttributeRegexp.ReplaceAllString(tag, draftAttribute)
+		})
+	case SlideKindMarkdown:
+		s.Content = markHiddenMarkdownSlides(s.Content)
+	}
+}
+
+// markHiddenMarkdownSlides replaces data-visibility="hidden" in <!-- .slide: --> comments with the draft attribute.
+func markHiddenMarkdownSlides(content string) string {
+	return markdownSlideAttributesRegexp.ReplaceAllStringFunc(content, func(comment string) string {
+		return hiddenAttributeRegexp.ReplaceAllString(comment, draftAttribute)
+	})
+}
+
+// hasDrafts returns true if the deck has the draft slides.
+func (d *Deck) hasDrafts() bool {
+	for _, slide := range d.AllSlides() {
+		if slide.Draft {
+			return true
+		}
+	}
+	return false
+}
+
+func draftAttributeOf(source *SlideSource) string {
+	if source.Draft {
+		return " " + draftAttribute
+	}
+	return ""
+}
@@ -10,7 +10,7 @@ import (
 //go:embed assets/presets
 var _initFS embed.FS
 
-//go:embed assets/index.html.tmpl assets/config.yml
+//go:embed assets/index.html.tmpl assets/handout.html.tmpl assets/config.yml
 var _defaultFS embed.FS
 
 //go:embed assets/reveal.js/dist assets/reveal.js/plugin
@@ -27,6 +27,7 @@ func presetFS(name string) (fs.FS, error) {
 
 // defaultFS returns the default files
 // - index.html.tmpl
+// - handout.html.tmpl
 // - config.yml
 func defaultFS() fs.FS {
 	f, _ := fs.Sub(_defaultFS, "assets")
@@ -0,0 +1,133 @@
+package revealjs
+
+import (
+	"bytes"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// removeSlides removes the slides for which remove returns true from the source and its content.
+func (s *SlideSource) removeSlides(remove func(slide *Slide) bool) error {
+	found := false
+	for _, slide := range s.AllSlides() {
+		if remove(slide) {
+			found = true
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	switch s.Kind {
+	case SlideKindHTML:
+		content, err := s.removeHTMLSlides(remove)
+		if err != nil {
+			return err
+		}
+		s.Content = content
+	case SlideKindMarkdown:
+		s.Content = s.removeMarkdownSlides(remove)
+	}
+	s.Slides = filterSlides(s.Slides, remove)
+	return nil
+}
+
+// filterSlides removes the slides for which remove returns true.
+// If the top of the vertical stack is removed, the next slide in the stack takes its place.
+func filterSlides(slides []*Slide, remove func(slide *Slide) bool) []*Slide {
+	result := make([]*Slide, 0, len(slides))
+	for _, slide := range slides {
+		stack := make([]*Slide, 0)
+		for _, s := range append([]*Slide{slide}, slide.Vertical...) {
+			if !remove(s) {
+				stack = append(stack, s)
+			}
+		}
+		if len(stack) == 0 {
+			continue
+		}
+		for _, s := range stack {
+			s.Vertical = nil
+		}
+		stack[0].Vertical = stack[1:]
+		result = append(result, stack[0])
+	}
+	return result
+}
+
+// removeHTMLSlides removes the <section> tags of the slides from the HTML.
+// The vertical stack is removed when all of its slides are removed.
+func (s *SlideSource) removeHTMLSlides(remove func(slide *Slide) bool) (string, error) {
+	nodes, err := html.ParseFragment(strings.NewReader(s.Content), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		return "", err
+	}
+	isSection := func(node *html.Node) bool {
+		return node.Type == html.ElementNode && node.DataAtom == atom.Section
+	}
+
+	var buf bytes.Buffer
+	for _, node := range nodes {
+		if isSection(node) {
+			stacked := false
+			for c := node.FirstChild; c != nil; {
+				next := c.NextSibling
+				if isSection(c) {
+					stacked = true
+					slide := newStackedHTMLSlide(node, c)
+					s.inherit(slide)
+					if remove(slide) {
+						node.RemoveChild(c)
+					}
+				}
+				c = next
+			}
+			if stacked {
+				empty := true
+				for c := node.FirstChild; c != nil; c = c.NextSibling {
+					if isSection(c) {
+						empty = false
+					}
+				}
+				if empty {
+					continue
+				}
+			} else {
+				slide := newHTMLSlide(node)
+				s.inherit(slide)
+				if remove(slide) {
+					continue
+				}
+			}
+		}
+		if err := html.Render(&buf, node); err != nil {
+			return "", err
+		}
+	}
+	return buf.String(), nil
+}
+
+// removeMarkdownSlides removes the slides from the markdown.
+func (s *SlideSource) removeMarkdownSlides(remove func(slide *Slide) bool) string {
+	horizontals := make([]string, 0)
+	for _, horizontal := range markdownSeparatorRegexp.Split(s.Content, -1) {
+		verticals := make([]string, 0)
+		for _, vertical := range markdownVerticalSeparatorRegexp.Split(horizontal, -1) {
+			slide := newMarkdownSlide(vertical)
+			s.inherit(slide)
+			if !remove(slide) {
+				verticals = append(verticals, vertical)
+			}
+		}
+		if len(verticals) > 0 {
+			horizontals = append(horizontals, strings.Join(verticals, "\n~~~\n"))
+		}
+	}
+	return strings.Join(horizontals, "\n---\n")
+}
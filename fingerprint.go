@@ -0,0 +1,203 @@
+package revealjs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// fingerprintLength is the length of the hash in the names of the fingerprinted files.
+const fingerprintLength = 8
+
+// fingerprintFiles renames the files in the build directory accepted by renamable with the hashes of their contents,
+// and rewrites the references to them in the HTML, markdown and CSS files.
+// The HTML and markdown files are not renamed since they are linked or fetched by their names.
+func fingerprintFiles(dir string, manifest *Manifest, renamable func(path string) bool) error {
+	renamed := map[string]string{}
+	styles := make([]*ManifestFile, 0)
+	pages := make([]*ManifestFile, 0)
+	for _, f := range manifest.Files {
+		switch {
+		case IsHTML(f.Path) || IsMarkdown(f.Path):
+			pages = append(pages, f)
+		case !renamable(f.Path):
+			continue
+		case path.Ext(f.Path) == ".css":
+			styles = append(styles, f)
+		default:
+			if err := renameWithFingerprint(dir, f, renamed); err != nil {
+				return err
+			}
+		}
+	}
+
+	// The CSS may import the other CSS, so fingerprint the imported CSS first
+	for len(styles) > 0 {
+		pending := make([]*ManifestFile, 0)
+		for _, f := range styles {
+			b, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(f.Path)))
+			if err != nil {
+				return err
+			}
+			if importsAny(f.Path, string(b), styles) {
+				pending = append(pending, f)
+			}
+		}
+		// Fingerprint all the rest if they import each other
+		if len(pending) == len(styles) {
+			pending = nil
+		}
+		for _, f := range styles {
+			if !containsFile(pending, f) {
+				if err := rewriteReferences(dir, f, renamed); err != nil {
+					return err
+				}
+				if err := renameWithFingerprint(dir, f, renamed); err != nil {
+					return err
+				}
+			}
+		}
+		styles = pending
+	}
+
+	for _, f := range pages {
+		if err := rewriteReferences(dir, f, renamed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fingerprintable returns true if the file can be renamed with the fingerprint.
+// The references in the scripts are not rewritten, so the scripts are renamed only if they are loaded from index.html,
+// such as 'dist/reveal.js' and the plugin scripts, and the other files in the plugin directories are not renamed
+// since the plugins may load them by their names.
+// The CSS files, and the files in 'assets' such as the images are renamed.
+func (r *RevealJS) fingerprintable(p string) bool {
+	if p == "dist/reveal.js" {
+		return true
+	}
+	for _, plugin := range r.config.Plugins() {
+		if p == path.Clean(strings.TrimPrefix(plugin.Src, "/")) {
+			return true
+		}
+	}
+	for _, dir := range r.pluginDirectories() {
+		if strings.HasPrefix(p, dir+"/") {
+			return false
+		}
+	}
+	switch path.Ext(p) {
+	case ".css":
+		return true
+	case ".js", ".mjs", ".map":
+		return false
+	}
+	return strings.HasPrefix(p, DirNameAssets+"/")
+}
+
+// renameWithFingerprint renames the file such as 'dist/theme/black.css' to 'dist/theme/black.<hash>.css'.
+func renameWithFingerprint(dir string, f *ManifestFile, renamed map[string]string) error {
+	ext := path.Ext(f.Path)
+	fingerprinted := strings.TrimSuffix(f.Path, ext) + "." + f.SHA256[:fingerprintLength] + ext
+	if err := os.Rename(filepath.Join(dir, filepath.FromSlash(f.Path)), filepath.Join(dir, filepath.FromSlash(fingerprinted))); err != nil {
+		return err
+	}
+	renamed[f.Path] = fingerprinted
+	f.Original, f.Path = f.Path, fingerprinted
+	return nil
+}
+
+// rewriteReferences rewrites the paths of the renamed files in the file,
+// which are relative to the root of the build directory or to the directory of the file.
+func rewriteReferences(dir string, f *ManifestFile, renamed map[string]string) error {
+	if len(renamed) == 0 {
+		return nil
+	}
+	replacements := map[string]string{}
+	for old, new := range renamed {
+		replacements[old] = new
+		if rel, err := filepath.Rel(path.Dir(f.Path), old); err == nil {
+			newRel, _ := filepath.Rel(path.Dir(f.Path), new)
+			replacements[filepath.ToSlash(rel)] = filepath.ToSlash(newRel)
+		}
+	}
+
+	file := filepath.Join(dir, filepath.FromSlash(f.Path))
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	content := replaceReferences(string(b), replacements)
+	if content == string(b) {
+		return nil
+	}
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		return err
+	}
+	hash := sha256.Sum256([]byte(content))
+	f.SHA256 = hex.EncodeToString(hash[:])
+	f.Size = int64(len(content))
+	// The rewritten file depends on the other files, so it's not reused in the next build
+	f.Source = ""
+	return nil
+}
+
+// replaceReferences replaces the paths in the content.
+// The paths are matched only if they are not a part of the other paths, such as 'my/a.png' for 'a.png'.
+func replaceReferences(content string, replacements map[string]string) string {
+	paths := make([]string, 0, len(replacements))
+	for p := range replacements {
+		paths = append(paths, regexp.QuoteMeta(p))
+	}
+	// Match the longest path first
+	sort.Slice(paths, func(i, j int) bool {
+		return len(paths[i]) > len(paths[j])
+	})
+	re := regexp.MustCompile(`(?:^|[^\w./-])(?:\./)?(` + strings.Join(paths, "|") + `)`)
+
+	var sb strings.Builder
+	last := 0
+	for _, match := range re.FindAllStringSubmatchIndex(content, -1) {
+		start, end := match[2], match[3]
+		if end < len(content) && isPathChar(content[end]) {
+			continue
+		}
+		sb.WriteString(content[last:start])
+		sb.WriteString(replacements[content[start:end]])
+		last = end
+	}
+	sb.WriteString(content[last:])
+	return sb.String()
+}
+
+func isPathChar(c byte) bool {
+	return c == '.' || c == '/' || c == '-' || c == '_' || isDigit(c) || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// importsAny returns true if the CSS references any of the other CSS files.
+func importsAny(cssPath string, css string, styles []*ManifestFile) bool {
+	for _, ref := range cssReferences(css) {
+		ref = path.Join(path.Dir(cssPath), ref)
+		for _, f := range styles {
+			if f.Path == ref && f.Path != cssPath {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsFile(files []*ManifestFile, f *ManifestFile) bool {
+	for _, file := range files {
+		if file == f {
+			return true
+		}
+	}
+	return false
+}
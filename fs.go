@@ -2,26 +2,31 @@ package revealjs
 
 import (
 	"io/fs"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
 const (
-	FileNameConfig        = "config.yml"
-	FileNameIndexHTMLTmpl = "index.html.tmpl"
-	FileNameIndexHTML     = "index.html"
-	DirNameSlides         = "slides"
-	DirNameAssets         = "assets"
+	FileNameConfig          = "config.yml"
+	FileNameIndexHTMLTmpl   = "index.html.tmpl"
+	FileNameHandoutHTMLTmpl = "handout.html.tmpl"
+	FileNameIndexHTML       = "index.html"
+	DirNameSlides           = "slides"
+	DirNameAssets           = "assets"
+	DirNameBuild            = "build"
 )
 
 // SlideResourceFS is a file system that provides slides and assets.
 // - *.md
 // - *.html
-// - slides/*.md
-// - slides/*.html
+// - slides/**/*.md
+// - slides/**/*.html
 // - assets/**/*
 // - config.yml
 // - index.html.tmpl
+// - handout.html.tmpl
 type SlideResourceFS struct {
 	fs fs.FS
 }
@@ -34,10 +39,16 @@ func (s *SlideResourceFS) Open(name string) (fs.File, error) {
 	if name == "." {
 		return s.fs.Open(name)
 	}
+	// Only the directories in 'slides' are vertical stacks, the other files are filtered below
+	if isSlidesDir(name) && !IsMarkdown(name) && !IsHTML(name) {
+		if info, err := fs.Stat(s.fs, name); err == nil && info.IsDir() {
+			return s.fs.Open(name)
+		}
+	}
 
 	dir, file := filepath.Split(name)
 	dir = filepath.ToSlash(dir)
-	if dir == "" || dir == DirNameSlides+"/" {
+	if dir == "" || isSlidesDir(strings.TrimSuffix(dir, "/")) {
 		if IsMarkdown(file) || IsHTML(file) {
 			return s.fs.Open(name)
 		}
@@ -45,14 +56,14 @@ func (s *SlideResourceFS) Open(name string) (fs.File, error) {
 	if s.hasPrefix(name, DirNameAssets) {
 		return s.fs.Open(name)
 	}
-	if dir == "" && (file == FileNameConfig || file == FileNameIndexHTMLTmpl) {
+	if dir == "" && (file == FileNameConfig || file == FileNameIndexHTMLTmpl || file == FileNameHandoutHTMLTmpl) {
 		return s.fs.Open(name)
 	}
 	return nil, fs.ErrNotExist
 }
 
 func (f *SlideResourceFS) ReadDir(name string) ([]fs.DirEntry, error) {
-	if name == "." || name == DirNameSlides {
+	if name == "." || isSlidesDir(name) {
 		entries, err := fs.ReadDir(f.fs, name)
 		if err != nil {
 			return nil, err
@@ -61,9 +72,12 @@ func (f *SlideResourceFS) ReadDir(name string) ([]fs.DirEntry, error) {
 		for _, entry := range entries {
 			if name == "." && entry.IsDir() && (entry.Name() == DirNameSlides || entry.Name() == DirNameAssets) {
 				filteredEntries = append(filteredEntries, entry)
+			} else if name != "." && entry.IsDir() {
+				// Directories in 'slides' are vertical stacks
+				filteredEntries = append(filteredEntries, entry)
 			} else if !entry.IsDir() && (IsMarkdown(entry.Name()) || IsHTML(entry.Name())) {
 				filteredEntries = append(filteredEntries, entry)
-			} else if name == "." && (entry.Name() == FileNameConfig || entry.Name() == FileNameIndexHTMLTmpl) {
+			} else if name == "." && (entry.Name() == FileNameConfig || entry.Name() == FileNameIndexHTMLTmpl || entry.Name() == FileNameHandoutHTMLTmpl) {
 				filteredEntries = append(filteredEntries, entry)
 			}
 		}
@@ -84,6 +98,53 @@ func (f *SlideResourceFS) hasPrefix(path string, prefix string) bool {
 	return strings.HasPrefix(path, prefix+filepath.FromSlash("/"))
 }
 
+// isSlidesDir returns true if the path is 'slides' directory or its subdirectory.
+func isSlidesDir(path string) bool {
+	return path == DirNameSlides || strings.HasPrefix(path, DirNameSlides+"/")
+}
+
+// stackOf returns the directory of the vertical stack that the slide file belongs to,
+// or empty string if the file is a horizontal slide.
+// Each directory in 'slides' is a vertical stack, e.g. 'slides/03-architecture/*.md'.
+func stackOf(file string) string {
+	parts := strings.Split(filepath.ToSlash(file), "/")
+	if len(parts) > 2 && parts[0] == DirNameSlides {
+		return parts[0] + "/" + parts[1]
+	}
+	return ""
+}
+
+// walkFilesSorted walks the files in the directory tree.
+// The entries of each directory are sorted by orderOf, and then by natural order of the names.
+func walkFilesSorted(fileSystem fs.FS, dir string, orderOf func(path string, entry fs.DirEntry) int, fn func(path string)) error {
+	entries, err := fs.ReadDir(fileSystem, dir)
+	if err != nil {
+		return err
+	}
+	orders := make(map[string]int, len(entries))
+	for _, entry := range entries {
+		orders[entry.Name()] = orderOf(path.Join(dir, entry.Name()), entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i].Name(), entries[j].Name()
+		if orders[a] != orders[b] {
+			return orders[a] < orders[b]
+		}
+		return naturalLess(a, b)
+	})
+	for _, entry := range entries {
+		p := path.Join(dir, entry.Name())
+		if entry.IsDir() {
+			if err := walkFilesSorted(fileSystem, p, orderOf, fn); err != nil {
+				return err
+			}
+		} else {
+			fn(p)
+		}
+	}
+	return nil
+}
+
 func IsMarkdown(path string) bool {
 	return filepath.Ext(path) == ".md"
 }
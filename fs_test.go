@@ -0,0 +1,36 @@
+package revealjs
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSlideResourceFS(t *testing.T) {
+	fsys := NewSlideResourceFS(fstest.MapFS{
+		"slides.md":                 {Data: []byte("# Slides")},
+		"config.yml":                {Data: []byte("")},
+		"secret.txt":                {Data: []byte("secret")},
+		"assets/logo.png":           {Data: []byte("png")},
+		"slides/01.md":              {Data: []byte("# 1")},
+		"slides/secret.txt":         {Data: []byte("secret")},
+		"slides/02-stack/01.html":   {Data: []byte("<h1>2</h1>")},
+		"slides/02-stack/key.pem":   {Data: []byte("key")},
+		"slides/02-stack/sub/x.pem": {Data: []byte("key")},
+		"notes/private.md":          {Data: []byte("private")},
+	})
+	for _, name := range []string{".", "slides.md", "config.yml", "assets/logo.png", "slides", "slides/01.md", "slides/02-stack", "slides/02-stack/01.html"} {
+		f, err := fsys.Open(name)
+		if err != nil {
+			t.Errorf("failed to open %s: %s", name, err)
+			continue
+		}
+		f.Close()
+	}
+	for _, name := range []string{"secret.txt", "slides/secret.txt", "slides/02-stack/key.pem", "slides/02-stack/sub/x.pem", "notes/private.md", "notes"} {
+		if _, err := fsys.Open(name); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("expected %s not to exist but %v", name, err)
+		}
+	}
+}
@@ -5,6 +5,7 @@ go 1.22.3
 require (
 	github.com/fsnotify/fsnotify v1.7.0
 	github.com/urfave/cli/v2 v2.27.2
+	golang.org/x/net v0.35.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
@@ -12,5 +13,5 @@ require (
 	github.com/cpuguy83/go-md2man/v2 v2.0.4 // indirect
 	github.com/russross/blackfriday/v2 v2.1.0 // indirect
 	github.com/xrash/smetrics v0.0.0-20240312152122-5f08fbb34913 // indirect
-	golang.org/x/sys v0.4.0 // indirect
+	golang.org/x/sys v0.30.0 // indirect
 )
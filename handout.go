@@ -0,0 +1,142 @@
+package revealjs
+
+import (
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	PaperSizeA4     = "A4"
+	PaperSizeLetter = "Letter"
+
+	DirNameHandoutImages = "handout"
+	FileNameHandoutHTML  = "handout.html"
+	FileNameHandoutPDF   = "handout.pdf"
+)
+
+// HandoutOptions is the options to generate the audience handout.
+type HandoutOptions struct {
+	// SlidesPerPage is the number of slides laid out on a page.
+	SlidesPerPage int
+	// PaperSize is the paper size of the pages, "A4" or "Letter".
+	PaperSize string
+	// Chromium is the browser used to take thumbnails of the slides and to print the PDF.
+	Chromium *Chromium
+}
+
+type handoutSlide struct {
+	Number string
+	Image  string
+	Notes  string
+}
+
+// BuildHandout generates a printable handout in dst.
+// Each slide of the deck is rendered as a thumbnail next to its speaker notes.
+//
+// The following files are generated:
+// - handout.html
+// - handout.pdf
+// - handout/*.png
+func (r *RevealJS) BuildHandout(dst string, options *HandoutOptions) error {
+	if options.SlidesPerPage <= 0 {
+		return fmt.Errorf("invalid number of slides per page: %d", options.SlidesPerPage)
+	}
+	if options.PaperSize != PaperSizeA4 && options.PaperSize != PaperSizeLetter {
+		return fmt.Errorf("unsupported paper size: %s", options.PaperSize)
+	}
+
+	deck, err := r.Deck()
+	if err != nil {
+		return err
+	}
+	deckSlides := deck.AllSlides()
+
+	// Build the deck to take thumbnails of the slides
+	deckDir, err := os.MkdirTemp("", "revealjs-handout-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(deckDir)
+	if err := r.buildLocal(deckDir); err != nil {
+		return err
+	}
+	deckURL, err := fileURL(filepath.Join(deckDir, FileNameIndexHTML))
+	if err != nil {
+		return err
+	}
+
+	imageDir := filepath.Join(dst, DirNameHandoutImages)
+	if err := os.MkdirAll(imageDir, 0700); err != nil {
+		return err
+	}
+	width, height := r.SlideSize()
+	slides := make([]handoutSlide, 0, len(deckSlides))
+	for _, slide := range deckSlides {
+		image := fmt.Sprintf("%s/slide-%d-%d.png", DirNameHandoutImages, slide.H, slide.V)
+		// Disable the transitions and controls so that they don't appear in the thumbnails.
+		url := fmt.Sprintf("%s?transition=none&controls=false&progress=false&slideNumber=false#/%d/%d", deckURL, slide.H, slide.V)
+		if err := options.Chromium.Screenshot(url, filepath.Join(dst, filepath.FromSlash(image)), width, height); err != nil {
+			return fmt.Errorf("failed to take thumbnail of slide %d/%d: %w", slide.H, slide.V, err)
+		}
+		slides = append(slides, handoutSlide{
+			Number: slide.Number(),
+			Image:  image,
+			Notes:  slide.Notes,
+		})
+	}
+
+	handoutHTML := filepath.Join(dst, FileNameHandoutHTML)
+	if err := r.generateHandoutHTML(handoutHTML, slides, options); err != nil {
+		return err
+	}
+	handoutURL, err := fileURL(handoutHTML)
+	if err != nil {
+		return err
+	}
+	return options.Chromium.PrintToPDF(handoutURL, filepath.Join(dst, FileNameHandoutPDF))
+}
+
+func (r *RevealJS) generateHandoutHTML(dst string, slides []handoutSlide, options *HandoutOptions) error {
+	b, err := fs.ReadFile(r.fs, FileNameHandoutHTMLTmpl)
+	if err != nil {
+		return err
+	}
+	tmpl, err := template.New(FileNameHandoutHTMLTmpl).Parse(string(b))
+	if err != nil {
+		return err
+	}
+
+	pages := make([][]handoutSlide, 0)
+	for i := 0; i < len(slides); i += options.SlidesPerPage {
+		end := min(i+options.SlidesPerPage, len(slides))
+		pages = append(pages, slides[i:end])
+	}
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return tmpl.Execute(f, map[string]interface{}{
+		"config":        r.config,
+		"pages":         pages,
+		"paperSize":     strings.ToLower(options.PaperSize),
+		"slidesPerPage": options.SlidesPerPage,
+	})
+}
+
+// SlideSize returns the size of the slides configured by 'revealjs.width' and 'revealjs.height'.
+func (r *RevealJS) SlideSize() (int, int) {
+	width, height := 960, 700
+	if w, ok := r.config.RevealJS["width"].(int); ok {
+		width = w
+	}
+	if h, ok := r.config.RevealJS["height"].(int); ok {
+		height = h
+	}
+	return width, height
+}
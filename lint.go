@@ -0,0 +1,433 @@
+package revealjs
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io/fs"
+	"math"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// LintSeverity is the severity of the problems found by a lint rule.
+type LintSeverity string
+
+const (
+	LintSeverityError   LintSeverity = "error"
+	LintSeverityWarning LintSeverity = "warning"
+	LintSeverityOff     LintSeverity = "off"
+)
+
+const (
+	LintRuleImageAlt     = "image-alt"
+	LintRuleHeadingOrder = "heading-order"
+	LintRuleSlideHeading = "slide-heading"
+	LintRuleContrast     = "contrast"
+	LintRuleHTMLLang     = "html-lang"
+)
+
+// lintRules is the default severity of the lint rules, used if not configured in 'lint.rules'.
+var lintRules = map[string]LintSeverity{
+	LintRuleImageAlt:     LintSeverityError,
+	LintRuleHeadingOrder: LintSeverityWarning,
+	LintRuleSlideHeading: LintSeverityWarning,
+	LintRuleContrast:     LintSeverityWarning,
+	LintRuleHTMLLang:     LintSeverityWarning,
+}
+
+// defaultMinContrast is the level AA of WCAG for the normal text.
+const defaultMinContrast = 4.5
+
+var (
+	markdownImageRegexp  = regexp.MustCompile(`!\[((?:[^\[\]]|\[[^\]]*\])*)\]\(\s*(?:<([^>]*)>|([^\s)]+))[^)]*\)`)
+	themeMainColorRegexp = regexp.MustCompile(`--r-main-color:\s*([^;}]+)`)
+)
+
+// builtinThemeTextColors is the text colors of the themes bundled with reveal.js,
+// used if the color can't be read from the theme CSS.
+var builtinThemeTextColors = map[string]string{
+	"black":          "#fff",
+	"black-contrast": "#fff",
+	"white":          "#222",
+	"white-contrast": "#000",
+	"league":         "#eee",
+	"beige":          "#333",
+	"sky":            "#333",
+	"night":          "#eee",
+	"serif":          "#000",
+	"simple":         "#000",
+	"solarized":      "#657b83",
+	"blood":          "#eee",
+	"moon":           "#93a1a1",
+	"dracula":        "#f8f8f2",
+}
+
+// severity returns the configured severity of the rule, or its default severity.
+func (l LintConfig) severity(rule string) LintSeverity {
+	if severity, ok := l.Rules[rule]; ok && severity != "" {
+		return severity
+	}
+	return lintRules[rule]
+}
+
+func (l LintConfig) minContrast() float64 {
+	if l.MinContrast > 0 {
+		return l.MinContrast
+	}
+	return defaultMinContrast
+}
+
+func (l LintConfig) validate() error {
+	for rule, severity := range l.Rules {
+		if _, ok := lintRules[rule]; !ok {
+			return fmt.Errorf("unknown lint rule '%s' in lint.rules", rule)
+		}
+		switch severity {
+		case "", LintSeverityError, LintSeverityWarning, LintSeverityOff:
+		default:
+			return fmt.Errorf("invalid severity '%s' of lint rule '%s', expected error, warning or off", severity, rule)
+		}
+	}
+	return nil
+}
+
+func (s LintSeverity) jsonSchema() map[string]interface{} {
+	return map[string]interface{}{"enum": []interface{}{LintSeverityError, LintSeverityWarning, LintSeverityOff, nil}}
+}
+
+// Lint checks the accessibility of the slides in the deck by the rules configured in 'lint' of the config,
+// and returns the problems located by the slide files and the slide numbers.
+func (r *RevealJS) Lint() ([]*Problem, error) {
+	config := r.config.Lint
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	deck, err := r.Deck()
+	if err != nil {
+		return nil, err
+	}
+	linter := &slideLinter{r: r, config: config, problems: make([]*Problem, 0), imageColors: map[string]*color.RGBA{}}
+	if c, ok := r.themeTextColor(); ok {
+		linter.textColor = &c
+	}
+	for _, slide := range deck.AllSlides() {
+		// The generated slides such as the agenda
+		if slide.Source() == nil || slide.Source().File == "" {
+			continue
+		}
+		linter.lint(slide)
+	}
+
+	var indexHTML bytes.Buffer
+	if err := r.GenerateIndexHTML(&indexHTML, &HTMLGeneratorParams{HotReload: false}); err != nil {
+		return nil, err
+	}
+	if !hasLang(indexHTML.String()) {
+		linter.report(&Problem{File: FileNameIndexHTMLTmpl}, LintRuleHTMLLang, "the page has no lang attribute, set 'lang' in config.yml")
+	}
+	sortProblems(linter.problems)
+	return linter.problems, nil
+}
+
+// LintErrors returns the number of the problems whose severity is error.
+func LintErrors(problems []*Problem) int {
+	n := 0
+	for _, problem := range problems {
+		if problem.Severity == LintSeverityError {
+			n++
+		}
+	}
+	return n
+}
+
+type slideLinter struct {
+	r        *RevealJS
+	config   LintConfig
+	problems []*Problem
+	// textColor is the text color of the theme, or nil if unknown.
+	textColor *color.RGBA
+	// imageColors is the average colors of the background images, or nil if not decodable.
+	imageColors map[string]*color.RGBA
+}
+
+func (l *slideLinter) report(problem *Problem, rule string, format string, args ...interface{}) {
+	severity := l.config.severity(rule)
+	if severity == LintSeverityOff {
+		return
+	}
+	problem.Rule = rule
+	problem.Severity = severity
+	problem.Message = fmt.Sprintf(format, args...)
+	l.problems = append(l.problems, problem)
+}
+
+func (l *slideLinter) lint(slide *Slide) {
+	at := func() *Problem {
+		return &Problem{File: slide.Source().File, Slide: slide.Number()}
+	}
+	content := slide.Content
+	if slide.Source().Kind == SlideKindMarkdown {
+		content = markdownToLintHTML(content)
+	}
+
+	prev := 0
+	headings := 0
+	tokenizer := html.NewTokenizer(strings.NewReader(content))
+	for {
+		tt := tokenizer.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			continue
+		}
+		token := tokenizer.Token()
+		switch token.DataAtom {
+		case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+			level := int(token.Data[1] - '0')
+			if prev > 0 && level > prev+1 {
+				l.report(at(), LintRuleHeadingOrder, "heading skips from h%d to h%d", prev, level)
+			}
+			prev = level
+			headings++
+		case atom.Img:
+			src, _ := tokenAttr(token, "src")
+			// <img alt=""> is a decorative image, and ![](image.png) is converted to <img> without alt
+			if _, ok := tokenAttr(token, "alt"); !ok {
+				l.report(at(), LintRuleImageAlt, "image '%s' has no alt text", src)
+			}
+		}
+	}
+	if headings == 0 {
+		l.report(at(), LintRuleSlideHeading, "slide has no heading")
+	}
+
+	if l.textColor == nil {
+		return
+	}
+	minContrast := l.config.minContrast()
+	if value, ok := slide.Attributes["data-background-color"]; ok {
+		if c, ok := parseCSSColor(value); ok {
+			if ratio := contrastRatio(*l.textColor, c); ratio < minContrast {
+				l.report(at(), LintRuleContrast, "contrast ratio %.2f between the text color of the theme and the background color '%s' is less than %g", ratio, value, minContrast)
+			}
+			return
+		}
+	}
+	if value, ok := slide.Attributes["data-background-image"]; ok {
+		if c := l.imageColor(value); c != nil {
+			if ratio := contrastRatio(*l.textColor, *c); ratio < minContrast {
+				l.report(at(), LintRuleContrast, "contrast ratio %.2f between the text color of the theme and the background image '%s' is less than %g", ratio, value, minContrast)
+			}
+		}
+	}
+}
+
+// imageColor returns the average color of the image in the file system, or nil if not decodable such as SVG and external URLs.
+func (l *slideLinter) imageColor(src string) *color.RGBA {
+	u, err := url.Parse(src)
+	if err != nil || u.Scheme != "" || u.Host != "" {
+		return nil
+	}
+	p := path.Clean(strings.TrimPrefix(u.Path, "/"))
+	if c, ok := l.imageColors[p]; ok {
+		return c
+	}
+	l.imageColors[p] = nil
+	b, err := fs.ReadFile(l.r.fs, p)
+	if err != nil {
+		return nil
+	}
+	img, _, err := image.Decode(bytes.NewReader(b))
+	if err != nil {
+		return nil
+	}
+	c := averageColor(img)
+	l.imageColors[p] = &c
+	return &c
+}
+
+// markdownToLintHTML converts the headings and the images in the markdown to HTML,
+// keeping the HTML in the markdown as is and ignoring the code.
+// The images without the alt text get no alt to be reported, and the decorative images are written as <img alt=""> in the markdown.
+func markdownToLintHTML(content string) string {
+	content = markdownCodeFenceRegexp.ReplaceAllStringFunc(content, blankOut)
+	content = markdownCodeSpanRegexp.ReplaceAllStringFunc(content, blankOut)
+	content = markdownImageRegexp.ReplaceAllStringFunc(content, func(s string) string {
+		match := markdownImageRegexp.FindStringSubmatch(s)
+		src := match[2] + match[3]
+		if match[1] == "" {
+			return fmt.Sprintf(`<img src="%s">`, html.EscapeString(src))
+		}
+		return fmt.Sprintf(`<img src="%s" alt="%s">`, html.EscapeString(src), html.EscapeString(match[1]))
+	})
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if matches := markdownHeadingRegexp.FindStringSubmatch(strings.TrimSpace(line)); matches != nil {
+			level := strings.Count(strings.Fields(line)[0], "#")
+			lines[i] = fmt.Sprintf("<h%d>%s</h%d>", level, matches[1], level)
+			continue
+		}
+		// The setext heading such as 'Title\n====='. The separators of the slides are already removed,
+		// so '---' right after a text is the underline of h2.
+		text := strings.TrimSpace(line)
+		if i+1 < len(lines) && text != "" && !strings.HasPrefix(text, "<") {
+			if underline := strings.TrimSpace(lines[i+1]); markdownSetextUnderlineRegexp.MatchString(underline) {
+				level := 1
+				if underline[0] == '-' {
+					level = 2
+				}
+				lines[i] = fmt.Sprintf("<h%d>%s</h%d>", level, text, level)
+				lines[i+1] = ""
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func tokenAttr(token html.Token, key string) (string, bool) {
+	for _, attr := range token.Attr {
+		if attr.Key == key {
+			return attr.Val, true
+		}
+	}
+	return "", false
+}
+
+// hasLang returns true if the html element of the page has non-empty lang attribute.
+func hasLang(page string) bool {
+	tokenizer := html.NewTokenizer(strings.NewReader(page))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return false
+		case html.StartTagToken:
+			token := tokenizer.Token()
+			if token.DataAtom == atom.Html {
+				lang, _ := tokenAttr(token, "lang")
+				return strings.TrimSpace(lang) != ""
+			}
+		}
+	}
+}
+
+// themeTextColor returns the text color of the theme read from --r-main-color in the theme CSS,
+// or the known color of the theme bundled with reveal.js.
+func (r *RevealJS) themeTextColor() (color.RGBA, bool) {
+	if b, err := fs.ReadFile(r.fs, path.Join("dist", "theme", r.config.Theme+".css")); err == nil {
+		if matches := themeMainColorRegexp.FindStringSubmatch(string(b)); matches != nil {
+			if c, ok := parseCSSColor(matches[1]); ok {
+				return c, true
+			}
+		}
+	}
+	if s, ok := builtinThemeTextColors[r.config.Theme]; ok {
+		return parseCSSColor(s)
+	}
+	return color.RGBA{}, false
+}
+
+var namedColors = map[string]string{
+	"black":  "#000",
+	"white":  "#fff",
+	"gray":   "#808080",
+	"grey":   "#808080",
+	"silver": "#c0c0c0",
+	"red":    "#f00",
+	"green":  "#008000",
+	"blue":   "#00f",
+	"yellow": "#ff0",
+	"orange": "#ffa500",
+	"purple": "#800080",
+	"navy":   "#000080",
+}
+
+// parseCSSColor parses the CSS color in #rgb, #rrggbb, rgb() or the basic color names, ignoring the alpha.
+func parseCSSColor(s string) (color.RGBA, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if named, ok := namedColors[s]; ok {
+		s = named
+	}
+	if hex, ok := strings.CutPrefix(s, "#"); ok {
+		switch len(hex) {
+		case 3, 4:
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		case 6, 8:
+			hex = hex[:6]
+		default:
+			return color.RGBA{}, false
+		}
+		v, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil {
+			return color.RGBA{}, false
+		}
+		return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 0xff}, true
+	}
+	for _, prefix := range []string{"rgb(", "rgba("} {
+		if args, ok := strings.CutPrefix(s, prefix); ok {
+			fields := strings.FieldsFunc(strings.TrimSuffix(args, ")"), func(r rune) bool { return r == ',' || r == ' ' || r == '/' })
+			if len(fields) < 3 {
+				return color.RGBA{}, false
+			}
+			var rgb [3]uint8
+			for i := range rgb {
+				v, err := strconv.ParseFloat(fields[i], 64)
+				if err != nil {
+					return color.RGBA{}, false
+				}
+				rgb[i] = uint8(math.Max(0, math.Min(255, v)))
+			}
+			return color.RGBA{rgb[0], rgb[1], rgb[2], 0xff}, true
+		}
+	}
+	return color.RGBA{}, false
+}
+
+// averageColor returns the average color of the image sampling at most 100x100 pixels.
+func averageColor(img image.Image) color.RGBA {
+	bounds := img.Bounds()
+	stepX := max(1, bounds.Dx()/100)
+	stepY := max(1, bounds.Dy()/100)
+	var r, g, b, n uint64
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += stepY {
+		for x := bounds.Min.X; x < bounds.Max.X; x += stepX {
+			c := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+			r, g, b, n = r+uint64(c.R), g+uint64(c.G), b+uint64(c.B), n+1
+		}
+	}
+	if n == 0 {
+		return color.RGBA{}
+	}
+	return color.RGBA{uint8(r / n), uint8(g / n), uint8(b / n), 0xff}
+}
+
+// contrastRatio returns the contrast ratio of WCAG between the colors, from 1 to 21.
+func contrastRatio(a, b color.RGBA) float64 {
+	la, lb := relativeLuminance(a), relativeLuminance(b)
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+func relativeLuminance(c color.RGBA) float64 {
+	channel := func(v uint8) float64 {
+		s := float64(v) / 255
+		if s <= 0.03928 {
+			return s / 12.92
+		}
+		return math.Pow((s+0.055)/1.055, 2.4)
+	}
+	return 0.2126*channel(c.R) + 0.7152*channel(c.G) + 0.0722*channel(c.B)
+}
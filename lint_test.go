@@ -0,0 +1,111 @@
+package revealjs
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	dataDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dataDir, "assets"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	writeImage(t, filepath.Join(dataDir, "assets", "light.png"), color.RGBA{240, 240, 240, 0xff})
+	writeImage(t, filepath.Join(dataDir, "assets", "dark.png"), color.RGBA{10, 10, 10, 0xff})
+	writeFile(t, filepath.Join(dataDir, "config.yml"), "theme: black\n")
+	writeFile(t, filepath.Join(dataDir, "slides.md"), "# Title\n\n![](assets/dark.png)\n![Dark](assets/dark.png)\n"+
+		"\n---\n\n<!-- .slide: data-background-image=\"assets/light.png\" -->\n## Light\n\n### Section\n\n##### Skipped\n"+
+		"\n---\n\nNo heading <img src=\"assets/dark.png\" alt=\"\">\n"+
+		"\n~~~\n\n<!-- .slide: data-background-color=\"#eeeeee\" -->\n# Bright\n\n```\n# Code\n![](code.png)\n```\n"+
+		"\n~~~\n\n<!-- .slide: data-background-image=\"assets/dark.png\" -->\n# Dark\n"+
+		"\n---\n\nSetext title\n============\n\nText\n"+
+		"\n---\n\nSetext section\n---\n\nText\n")
+	writeFile(t, filepath.Join(dataDir, "slides.html"), "<section><h2>HTML</h2><img src=\"assets/dark.png\"></section>\n")
+
+	lint := func(overrides ...string) []string {
+		t.Helper()
+		r, err := NewRevealJS(dataDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, s := range overrides {
+			override, err := ParseConfigOverride(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := r.OverrideConfig(override); err != nil {
+				t.Fatal(err)
+			}
+		}
+		problems, err := r.Lint()
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual := make([]string, 0, len(problems))
+		for _, problem := range problems {
+			actual = append(actual, problem.String())
+		}
+		return actual
+	}
+	assertEqual(t, lint(), []string{
+		"index.html.tmpl: warning: the page has no lang attribute, set 'lang' in config.yml [html-lang]",
+		"slides.html (slide 1): error: image 'assets/dark.png' has no alt text [image-alt]",
+		// The decorative image is written as <img alt=""> in slide 4
+		"slides.md (slide 2): error: image 'assets/dark.png' has no alt text [image-alt]",
+		"slides.md (slide 3): warning: heading skips from h3 to h5 [heading-order]",
+		"slides.md (slide 3): warning: contrast ratio 1.14 between the text color of the theme and the background image 'assets/light.png' is less than 4.5 [contrast]",
+		"slides.md (slide 4): warning: slide has no heading [slide-heading]",
+		"slides.md (slide 4.2): warning: contrast ratio 1.16 between the text color of the theme and the background color '#eeeeee' is less than 4.5 [contrast]",
+	})
+
+	// The rules are configured by 'lint'
+	assertEqual(t, lint("lang=en", "lint.rules={image-alt: warning, heading-order: off, slide-heading: off}", "lint.minContrast=1.15"), []string{
+		"slides.html (slide 1): warning: image 'assets/dark.png' has no alt text [image-alt]",
+		"slides.md (slide 2): warning: image 'assets/dark.png' has no alt text [image-alt]",
+		"slides.md (slide 3): warning: contrast ratio 1.14 between the text color of the theme and the background image 'assets/light.png' is less than 1.15 [contrast]",
+	})
+
+	r, err := NewRevealJS(dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.OverrideConfig(ConfigOverride{Key: "lint.rules", Value: "{unknown: error}"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Lint(); err == nil {
+		t.Error("expected error for the unknown rule")
+	}
+}
+
+func TestContrastRatio(t *testing.T) {
+	black, _ := parseCSSColor("#000")
+	white, _ := parseCSSColor("rgb(255, 255, 255)")
+	gray, _ := parseCSSColor("#777777")
+	assertEqual(t, contrastRatio(black, white), 21.0)
+	assertEqual(t, contrastRatio(white, white), 1.0)
+	if ratio := contrastRatio(white, gray); ratio < 4.47 || ratio > 4.48 {
+		t.Errorf("unexpected contrast ratio %f", ratio)
+	}
+}
+
+func writeImage(t *testing.T, path string, c color.Color) {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -20,6 +20,15 @@ func (m *Markdown) WithoutYAMLHeader() string {
 	return yamlHeaderRegexp.ReplaceAllString(m.content, "")
 }
 
+// yamlHeaderText returns the YAML header without parsing, or empty string if not exist.
+func (m *Markdown) yamlHeaderText() string {
+	matches := yamlHeaderRegexp.FindStringSubmatch(m.content)
+	if len(matches) == 2 {
+		return matches[1]
+	}
+	return ""
+}
+
 func (m *Markdown) YAMLHeader() (map[string]interface{}, error) {
 	header := make(map[string]interface{})
 	matches := yamlHeaderRegexp.FindStringSubmatch(m.content)
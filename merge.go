@@ -0,0 +1,152 @@
+package revealjs
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// mergeStrategy is how a value of the config is merged into the config it overrides.
+// It is given by the YAML tag of the value, such as `plugins: !append [RevealMath]`.
+type mergeStrategy string
+
+const (
+	// mergeDefault merges the maps deeply, and replaces the other values.
+	mergeDefault mergeStrategy = ""
+	// mergeReplace replaces the map or the list instead of merging.
+	mergeReplace mergeStrategy = "!replace"
+	// mergeAppend appends the items to the list.
+	mergeAppend mergeStrategy = "!append"
+	// mergeRemove removes the items from the list.
+	mergeRemove mergeStrategy = "!remove"
+	// mergeUnset unsets the value, given by `null`.
+	mergeUnset mergeStrategy = "!!null"
+)
+
+// mergeStrategies is the merge strategies of the values in the config, keyed by the path such as 'revealjs.autoAnimateStyles'.
+type mergeStrategies map[string]mergeStrategy
+
+// collect collects the merge strategies from the YAML tags, and removes the tags so that the node can be decoded.
+func (s mergeStrategies) collect(node *yaml.Node, path string) error {
+	switch strategy := mergeStrategy(node.Tag); strategy {
+	case mergeReplace, mergeAppend, mergeRemove:
+		if node.Kind != yaml.SequenceNode && (strategy != mergeReplace || node.Kind != yaml.MappingNode) {
+			return fmt.Errorf("%s is not applicable to '%s' at line %d", strategy, path, node.Line)
+		}
+		s[path] = strategy
+		node.Tag = ""
+	default:
+		if strings.HasPrefix(node.Tag, "!") && !strings.HasPrefix(node.Tag, "!!") {
+			return fmt.Errorf("unknown tag %s of '%s' at line %d", node.Tag, path, node.Line)
+		}
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			if err := s.collect(child, path); err != nil {
+				return err
+			}
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			if path != "" {
+				key = path + "." + key
+			}
+			if err := s.collect(value, key); err != nil {
+				return err
+			}
+		}
+	case yaml.ScalarNode:
+		if path != "" && node.ShortTag() == string(mergeUnset) {
+			s[path] = mergeUnset
+		}
+	}
+	return nil
+}
+
+// sub returns the merge strategies under the path.
+func (s mergeStrategies) sub(path string) mergeStrategies {
+	sub := mergeStrategies{}
+	for p, strategy := range s {
+		if rest, ok := strings.CutPrefix(p, path+"."); ok {
+			sub[rest] = strategy
+		}
+	}
+	return sub
+}
+
+// mergeMap merges other into a copy of base.
+func (s mergeStrategies) mergeMap(base, other map[string]interface{}, path string) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(other))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range other {
+		p := path + "." + k
+		switch s[p] {
+		case mergeUnset:
+			delete(merged, k)
+		case mergeReplace:
+			merged[k] = v
+		default:
+			merged[k] = s.mergeValue(merged[k], v, p)
+		}
+	}
+	return merged
+}
+
+func (s mergeStrategies) mergeValue(base, other interface{}, path string) interface{} {
+	switch o := other.(type) {
+	case map[string]interface{}:
+		if b, ok := base.(map[string]interface{}); ok {
+			return s.mergeMap(b, o, path)
+		}
+	case []interface{}:
+		b, _ := base.([]interface{})
+		return mergeList(b, o, s[path])
+	}
+	return other
+}
+
+// mergeList merges the list other into base without modifying base.
+func mergeList[T any](base, other []T, strategy mergeStrategy) []T {
+	switch strategy {
+	case mergeUnset:
+		return nil
+	case mergeAppend:
+		return append(slices.Clip(base), other...)
+	case mergeRemove:
+		return slices.DeleteFunc(slices.Clone(base), func(item T) bool {
+			return slices.ContainsFunc(other, func(removed T) bool {
+				return sameListItem(item, removed)
+			})
+		})
+	}
+	return other
+}
+
+// sameListItem returns true if the items are equal or have the same name,
+// so that `!remove [RevealMath]` removes `{name: RevealMath, src: ...}`.
+func sameListItem(a, b interface{}) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	name := listItemName(a)
+	return name != "" && name == listItemName(b)
+}
+
+func listItemName(item interface{}) string {
+	switch v := item.(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		name, _ := v["name"].(string)
+		return name
+	}
+	return ""
+}
@@ -0,0 +1,174 @@
+package revealjs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// overflowResultID is the id of the element the overflow script writes the results to.
+const overflowResultID = "revealcli-overflow"
+
+// overflowScript visits every slide and fragment state after reveal.js is ready,
+// and writes the states whose content is larger than the slide size as JSON.
+// The size is measured before scaling, so that it's comparable with 'revealjs.width' and 'revealjs.height'.
+const overflowScript = `<script>
+(function() {
+	function check() {
+		var width = %d, height = %d, results = [];
+		Reveal.getSlides().forEach(function(slide) {
+			var indices = Reveal.getIndices(slide);
+			var fragments = slide.querySelectorAll('.fragment[data-fragment-index]');
+			var last = -1;
+			fragments.forEach(function(fragment) {
+				last = Math.max(last, parseInt(fragment.getAttribute('data-fragment-index'), 10));
+			});
+			for (var f = -1; f <= last; f++) {
+				Reveal.slide(indices.h, indices.v || 0, f);
+				var w = slide.scrollWidth, h = slide.scrollHeight;
+				if (w > width + 1 || h > height + 1) {
+					results.push({h: indices.h, v: indices.v || 0, f: f, width: w, height: h});
+				}
+			}
+		});
+		var result = document.createElement('script');
+		result.type = 'application/json';
+		result.id = '%s';
+		result.textContent = JSON.stringify(results).replace(/</g, '\\u003c');
+		document.body.appendChild(result);
+	}
+	if (Reveal.isReady()) {
+		check();
+	} else {
+		Reveal.on('ready', check);
+	}
+})();
+</script>
+`
+
+// OverflowCheckOptions is the options of CheckOverflow.
+type OverflowCheckOptions struct {
+	// Chromium is the browser laying out the slides.
+	Chromium *Chromium
+	// ScreenshotDir is the directory to save the screenshots of the overflowing slides, or empty not to save.
+	ScreenshotDir string
+}
+
+// slideOverflow is a slide or a fragment state whose content is larger than the slide size.
+type slideOverflow struct {
+	H int `json:"h"`
+	V int `json:"v"`
+	// F is the index of the fragment shown last, or -1 before any fragment is shown.
+	F      int `json:"f"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// CheckOverflow builds the deck and lays out every slide and fragment state in the headless Chromium,
+// and returns the slides whose content is larger than 'revealjs.width' and 'revealjs.height' and gets clipped.
+// The screenshots of the overflowing slides are saved in ScreenshotDir.
+func (r *RevealJS) CheckOverflow(options *OverflowCheckOptions) ([]*Problem, error) {
+	deck, err := r.Deck()
+	if err != nil {
+		return nil, err
+	}
+	deckDir, err := os.MkdirTemp("", "revealjs-overflow-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(deckDir)
+	if err := r.buildLocal(deckDir); err != nil {
+		return nil, err
+	}
+
+	width, height := r.SlideSize()
+	indexHTML := filepath.Join(deckDir, FileNameIndexHTML)
+	if err := injectScript(indexHTML, fmt.Sprintf(overflowScript, width, height, overflowResultID)); err != nil {
+		return nil, err
+	}
+	deckURL, err := fileURL(indexHTML)
+	if err != nil {
+		return nil, err
+	}
+	dom, err := options.Chromium.DumpDOM(deckURL+"?transition=none", width, height)
+	if err != nil {
+		return nil, err
+	}
+	overflows, err := parseOverflowResult(dom)
+	if err != nil {
+		return nil, err
+	}
+
+	problems := make([]*Problem, 0, len(overflows))
+	for _, overflow := range overflows {
+		problem := &Problem{Slide: (&Slide{H: overflow.H, V: overflow.V}).Number()}
+		for _, slide := range deck.AllSlides() {
+			if slide.H == overflow.H && slide.V == overflow.V && slide.Source() != nil {
+				problem.File = slide.Source().File
+			}
+		}
+		state := ""
+		if overflow.F >= 0 {
+			state = fmt.Sprintf(" at fragment %d", overflow.F)
+		}
+		problem.Message = fmt.Sprintf("content %dx%d%s exceeds the slide size %dx%d", overflow.Width, overflow.Height, state, width, height)
+		if options.ScreenshotDir != "" {
+			// The directory is created only if any slide overflows
+			if err := os.MkdirAll(options.ScreenshotDir, 0700); err != nil {
+				return nil, err
+			}
+			screenshot := filepath.Join(options.ScreenshotDir, fmt.Sprintf("slide-%d-%d-%d.png", overflow.H, overflow.V, overflow.F+1))
+			url := fmt.Sprintf("%s?transition=none&controls=false&progress=false&slideNumber=false#/%d/%d/%d", deckURL, overflow.H, overflow.V, overflow.F)
+			if err := options.Chromium.Screenshot(url, screenshot, width, height); err != nil {
+				return nil, fmt.Errorf("failed to take screenshot of slide %s: %w", problem.Slide, err)
+			}
+			problem.Message += fmt.Sprintf(", see %s", screenshot)
+		}
+		problems = append(problems, problem)
+	}
+	sortProblems(problems)
+	return problems, nil
+}
+
+// injectScript inserts the script at the end of the body of the page.
+func injectScript(file string, script string) error {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	i := bytes.LastIndex(b, []byte("</body>"))
+	if i < 0 {
+		return fmt.Errorf("</body> not found in %s", file)
+	}
+	injected := append(append(append([]byte{}, b[:i]...), script...), b[i:]...)
+	return os.WriteFile(file, injected, 0600)
+}
+
+// parseOverflowResult reads the results written by overflowScript from the DOM.
+func parseOverflowResult(dom string) ([]*slideOverflow, error) {
+	tokenizer := html.NewTokenizer(strings.NewReader(dom))
+	found := false
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return nil, fmt.Errorf("the result of the overflow check not found, reveal.js may have failed to start")
+		case html.StartTagToken:
+			token := tokenizer.Token()
+			id, _ := tokenAttr(token, "id")
+			found = id == overflowResultID
+		case html.TextToken:
+			if found {
+				overflows := make([]*slideOverflow, 0)
+				if err := json.Unmarshal(tokenizer.Text(), &overflows); err != nil {
+					return nil, fmt.Errorf("failed to parse the result of the overflow check: %w", err)
+				}
+				return overflows, nil
+			}
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package revealjs
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// fakeChromium dumps the results of the overflow check if the script is injected and the deck is not based on the remote URL,
+// and writes the screenshots.
+const fakeChromium = `#!/bin/sh
+for arg in "$@"; do
+	case "$arg" in
+		--screenshot=*) echo png > "${arg#--screenshot=}" ;;
+		file://*) url="$arg" ;;
+	esac
+done
+case "$*" in
+	*--dump-dom*)
+		file="${url#file://}"
+		grep -q revealcli-overflow "${file%%\?*}" || exit 1
+		grep -q "<base" "${file%%\?*}" && exit 1
+		echo '<html><body><script type="application/json" id="revealcli-overflow">[{"h":1,"v":0,"f":-1,"width":960,"height":812},{"h":1,"v":1,"f":2,"width":1200,"height":700}]</script></body></html>'
+		;;
+esac
+`
+
+func TestCheckOverflow(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake chromium is a shell script")
+	}
+	dataDir := t.TempDir()
+	writeFile(t, filepath.Join(dataDir, "01.md"), "# First\n")
+	writeFile(t, filepath.Join(dataDir, "02.md"), "# Long\n\n~~~\n\n# Wide\n")
+	writeFile(t, filepath.Join(dataDir, FileNameConfig), "baseURL: https://example.com/talks/\n")
+	chromium := filepath.Join(t.TempDir(), "chromium")
+	if err := os.WriteFile(chromium, []byte(fakeChromium), 0700); err != nil {
+		t.Fatal(err)
+	}
+	screenshotDir := filepath.Join(t.TempDir(), "overflow")
+
+	r, err := NewRevealJS(dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	problems, err := r.CheckOverflow(&OverflowCheckOptions{
+		Chromium:      &Chromium{chromium},
+		ScreenshotDir: screenshotDir,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := make([]string, 0, len(problems))
+	for _, problem := range problems {
+		actual = append(actual, problem.String())
+	}
+	assertEqual(t, actual, []string{
+		"02.md (slide 2): content 960x812 exceeds the slide size 960x700, see " + filepath.Join(screenshotDir, "slide-1-0-0.png"),
+		"02.md (slide 2.2): content 1200x700 at fragment 2 exceeds the slide size 960x700, see " + filepath.Join(screenshotDir, "slide-1-1-3.png"),
+	})
+	assertExist(t, filepath.Join(screenshotDir, "slide-1-0-0.png"), true)
+	assertExist(t, filepath.Join(screenshotDir, "slide-1-1-3.png"), true)
+
+	// The screenshot directory is not created without the overflowing slides
+	noOverflow := strings.NewReplacer(`[{"h":1,"v":0,"f":-1,"width":960,"height":812},{"h":1,"v":1,"f":2,"width":1200,"height":700}]`, "[]").Replace(fakeChromium)
+	if err := os.WriteFile(chromium, []byte(noOverflow), 0700); err != nil {
+		t.Fatal(err)
+	}
+	cleanDir := filepath.Join(t.TempDir(), "overflow")
+	problems, err = r.CheckOverflow(&OverflowCheckOptions{
+		Chromium:      &Chromium{chromium},
+		ScreenshotDir: cleanDir,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, len(problems), 0)
+	assertExist(t, cleanDir, false)
+}
+
+func TestParseOverflowResult(t *testing.T) {
+	if _, err := parseOverflowResult("<html><body></body></html>"); err == nil {
+		t.Error("expected error for the page without the result")
+	}
+	overflows, err := parseOverflowResult(`<html><body><div id="x">[1]</div><script type="application/json" id="revealcli-overflow">[]</script></body></html>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, len(overflows), 0)
+}
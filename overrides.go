@@ -0,0 +1,193 @@
+package revealjs
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvConfigPrefix is the prefix of the environment variables overriding the config.
+// The rest of the name is the config key separated by '_' instead of '.', such as REVEALCLI_THEME or REVEALCLI_REVEALJS_CONTROLS.
+const EnvConfigPrefix = "REVEALCLI_"
+
+// overridableKeys is the config keys that can be overridden, and their sub keys.
+// nil means the key doesn't have sub keys.
+var overridableKeys = map[string][]string{
+	"slides":      nil,
+	"title":       nil,
+	"lang":        nil,
+	"theme":       nil,
+	"plugins":     nil,
+	"revealjs":    {},
+	"agenda":      {"enabled", "title"},
+	"buildDir":    nil,
+	"baseURL":     nil,
+	"prune":       nil,
+	"clean":       nil,
+	"fingerprint": nil,
+	"lint":        {"rules", "minContrast"},
+}
+
+// stringKeys is the config keys whose values are used as-is without parsing as YAML.
+var stringKeys = []string{"title", "lang", "theme", "agenda.title", "buildDir", "baseURL"}
+
+// ConfigOverride overrides a config value after loading config.yml and the front matters.
+type ConfigOverride struct {
+	// Key is the config key such as 'theme' or 'revealjs.controls'.
+	Key string
+	// Value is parsed as YAML, such as 'false', '10' or '[a, b]'.
+	Value string
+	// env is true if the key is given by the environment variable name.
+	env bool
+}
+
+// ParseConfigOverride parses 'key=value' such as 'revealjs.controls=false'.
+func ParseConfigOverride(s string) (ConfigOverride, error) {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok || key == "" {
+		return ConfigOverride{}, fmt.Errorf("invalid config override '%s', expected 'key=value'", s)
+	}
+	return ConfigOverride{Key: key, Value: value}, nil
+}
+
+// ConfigOverridesFromEnv returns the config overrides given by the environment variables with EnvConfigPrefix.
+func ConfigOverridesFromEnv(environ []string) []ConfigOverride {
+	overrides := make([]ConfigOverride, 0)
+	for _, env := range environ {
+		name, value, _ := strings.Cut(env, "=")
+		key, ok := strings.CutPrefix(name, EnvConfigPrefix)
+		if !ok || name == EnvChromium {
+			continue
+		}
+		override := ConfigOverride{Key: key, Value: value, env: true}
+		if _, err := (&Config{}).resolveOverrideKey(override); err != nil {
+			log.Printf("ignored environment variable %s: %s", name, err)
+			continue
+		}
+		overrides = append(overrides, override)
+	}
+	return overrides
+}
+
+// source returns the option or the environment variable giving the override.
+func (o ConfigOverride) source() string {
+	if o.env {
+		return o.String()
+	}
+	return "--set " + o.Key
+}
+
+func (o ConfigOverride) String() string {
+	if o.env {
+		return EnvConfigPrefix + o.Key
+	}
+	return o.Key
+}
+
+// OverrideConfig adds the config overrides and reloads the config.
+// The overrides are applied in the order they are added.
+func (r *RevealJS) OverrideConfig(overrides ...ConfigOverride) error {
+	r.overrides = append(r.overrides, overrides...)
+	return r.ReloadConfig()
+}
+
+func (c *Config) applyOverrides(overrides []ConfigOverride) error {
+	for _, override := range overrides {
+		path, err := c.resolveOverrideKey(override)
+		if err != nil {
+			return err
+		}
+		if err := c.applyOverride(path, override); err != nil {
+			return fmt.Errorf("failed to override '%s': %w", override, err)
+		}
+	}
+	return nil
+}
+
+// resolveOverrideKey resolves the key to the path of the config.
+// The keys are matched case-insensitively so that the environment variable names can be resolved.
+func (c *Config) resolveOverrideKey(override ConfigOverride) ([]string, error) {
+	var top, sub string
+	if override.env {
+		name := strings.ToLower(override.Key)
+		for key := range overridableKeys {
+			if name == strings.ToLower(key) {
+				top = key
+			} else if rest, ok := strings.CutPrefix(name, strings.ToLower(key)+"_"); ok {
+				top, sub = key, rest
+			}
+		}
+	} else {
+		first, rest, _ := strings.Cut(override.Key, ".")
+		for key := range overridableKeys {
+			if strings.EqualFold(first, key) {
+				top, sub = key, rest
+			}
+		}
+	}
+	if top == "" {
+		return nil, fmt.Errorf("unknown config key '%s'", override.Key)
+	}
+
+	subKeys := overridableKeys[top]
+	if sub == "" {
+		return []string{top}, nil
+	}
+	if subKeys == nil {
+		return nil, fmt.Errorf("config key '%s' doesn't have '%s'", top, sub)
+	}
+	if top == "revealjs" {
+		subKeys = make([]string, 0, len(c.RevealJS)+len(properties))
+		for k := range c.RevealJS {
+			subKeys = append(subKeys, k)
+		}
+		for k := range properties {
+			subKeys = append(subKeys, k)
+		}
+	}
+	for _, key := range subKeys {
+		if strings.EqualFold(sub, key) {
+			return []string{top, key}, nil
+		}
+	}
+	if top == "revealjs" {
+		// Any reveal.js config can be given
+		return []string{top, sub}, nil
+	}
+	return nil, fmt.Errorf("unknown config key '%s.%s'", top, sub)
+}
+
+func (c *Config) applyOverride(path []string, override ConfigOverride) error {
+	var v interface{} = override.Value
+	if !slices.Contains(stringKeys, strings.Join(path, ".")) {
+		if err := yaml.Unmarshal([]byte(override.Value), &v); err != nil {
+			return err
+		}
+	}
+	m := map[string]interface{}{}
+	if len(path) == 1 {
+		m[path[0]] = v
+	} else {
+		m[path[0]] = map[string]interface{}{path[1]: v}
+	}
+	b, err := yaml.Marshal(m)
+	if err != nil {
+		return err
+	}
+	other, err := doLoadConfigFile(bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	other.setSource(override.source())
+	for k, v := range other.RevealJS {
+		if _, err := c.valueToString(k, v); err != nil {
+			return err
+		}
+	}
+	c.OverrideWith(other)
+	return nil
+}
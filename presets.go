@@ -65,7 +65,7 @@ func (f *Preset) extractAll(destDir string, options *GenerateOptions) error {
 		if path == "." {
 			return nil
 		}
-		if path == "index.html.tmpl" && !options.GenerateHTMLTemplate {
+		if (path == "index.html.tmpl" || path == "handout.html.tmpl") && !options.GenerateHTMLTemplate {
 			return nil
 		}
 		if path == "config.yml" && !options.GenerateConfig {
@@ -0,0 +1,90 @@
+package revealjs
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// Profile is a variant of the deck for an audience, selected by `--profile` option.
+//
+// The config in the profile overrides the config of the deck, and the slides are selected by their tags.
+//
+//	profiles:
+//	  public:
+//	    title: Public talk
+//	    excludeTags: [internal]
+//	  short:
+//	    tags: [short]
+//	    revealjs:
+//	      controls: false
+type Profile struct {
+	Config `yaml:",inline"`
+	// Tags selects only the slides that have one of the tags.
+	Tags []string `yaml:"tags,omitempty"`
+	// ExcludeTags excludes the slides that have one of the tags.
+	ExcludeTags []string `yaml:"excludeTags,omitempty"`
+}
+
+// filtersSlides returns true if the profile selects the slides by tags.
+func (p *Profile) filtersSlides() bool {
+	return len(p.Tags) > 0 || len(p.ExcludeTags) > 0
+}
+
+// selects returns true if the slide is included in the profile.
+func (p *Profile) selects(slide *Slide) bool {
+	hasAny := func(tags []string) bool {
+		for _, tag := range tags {
+			if slices.Contains(slide.Tags, tag) {
+				return true
+			}
+		}
+		return false
+	}
+	if hasAny(p.ExcludeTags) {
+		return false
+	}
+	return len(p.Tags) == 0 || hasAny(p.Tags)
+}
+
+// UseProfile selects the profile defined in 'profiles' of config.yml and reloads the config.
+// An empty name selects no profile.
+func (r *RevealJS) UseProfile(name string) error {
+	r.profileName = name
+	if err := r.ReloadConfig(); err != nil {
+		r.profileName = ""
+		return err
+	}
+	return nil
+}
+
+// ProfileName returns the name of the selected profile, or empty if no profile is selected.
+func (r *RevealJS) ProfileName() string {
+	return r.profileName
+}
+
+// profile returns the selected profile, or nil if no profile is selected.
+func (r *RevealJS) profile() *Profile {
+	if r.profileName == "" {
+		return nil
+	}
+	return r.config.Profiles[r.profileName]
+}
+
+// applyProfile overrides the config with the selected profile.
+func (r *RevealJS) applyProfile(c *Config) error {
+	if r.profileName == "" {
+		return nil
+	}
+	profile, ok := c.Profiles[r.profileName]
+	if !ok || profile == nil {
+		names := make([]string, 0, len(c.Profiles))
+		for name := range c.Profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("profile '%s' is not defined (defined profiles: %v)", r.profileName, names)
+	}
+	c.OverrideWith(&profile.Config)
+	return nil
+}
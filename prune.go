@@ -0,0 +1,152 @@
+package revealjs
+
+import (
+	"io/fs"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	cssURLRegexp    = regexp.MustCompile(`url\(\s*['"]?([^'")]+)['"]?\s*\)`)
+	cssImportRegexp = regexp.MustCompile(`@import\s+['"]([^'"]+)['"]`)
+)
+
+// unusedFiles returns the files not referenced from index.html, or from the files referenced from index.html.
+// The files not skipped are the candidates, such as the themes, the plugins and the assets of reveal.js and the slides.
+//
+// A file is referenced if its path appears in the referencing file not as a part of a longer path,
+// so that the paths in the embedded slides, the inline styles and the scripts are detected regardless of their syntax,
+// and the paths in CSS are resolved relative to the CSS file.
+// The directories of the plugins enabled in the config are kept as a whole,
+// since the plugins may load their files dynamically by the paths not appearing in the scripts.
+func (r *RevealJS) unusedFiles(indexHTML []byte, skip func(path string) bool) ([]*ManifestFile, error) {
+	candidates := make(map[string]int64)
+	if err := fs.WalkDir(r.fs, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || skip(p) {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		candidates[p] = info.Size()
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	used := map[string]bool{}
+	queue := []string{FileNameIndexHTML}
+	for _, dir := range r.pluginDirectories() {
+		for candidate := range candidates {
+			if strings.HasPrefix(candidate, dir+"/") {
+				used[candidate] = true
+				if isReferencingFile(candidate) {
+					queue = append(queue, candidate)
+				}
+			}
+		}
+	}
+	contents := map[string]string{FileNameIndexHTML: string(indexHTML)}
+	for len(queue) > 0 {
+		referencing := queue[0]
+		queue = queue[1:]
+		content, ok := contents[referencing]
+		if !ok {
+			b, err := fs.ReadFile(r.fs, referencing)
+			if err != nil {
+				return nil, err
+			}
+			content = string(b)
+		}
+		references := map[string]bool{}
+		if path.Ext(referencing) == ".css" {
+			for _, ref := range cssReferences(content) {
+				references[path.Join(path.Dir(referencing), ref)] = true
+			}
+		}
+		for candidate := range candidates {
+			if used[candidate] {
+				continue
+			}
+			if references[candidate] || containsPath(content, candidate) || containsPath(content, (&url.URL{Path: candidate}).EscapedPath()) {
+				used[candidate] = true
+				if isReferencingFile(candidate) {
+					queue = append(queue, candidate)
+				}
+			}
+		}
+	}
+
+	unused := make([]*ManifestFile, 0)
+	for candidate, size := range candidates {
+		if !used[candidate] {
+			unused = append(unused, &ManifestFile{Path: candidate, Size: size})
+		}
+	}
+	sort.Slice(unused, func(i, j int) bool {
+		return unused[i].Path < unused[j].Path
+	})
+	return unused, nil
+}
+
+// pluginDirectories returns the directories of the local plugin scripts enabled in the config such as 'plugin/math'.
+// The scripts directly in the top directories such as 'assets/plugin.js' don't keep their directories.
+func (r *RevealJS) pluginDirectories() []string {
+	dirs := make([]string, 0)
+	for _, plugin := range r.config.Plugins() {
+		u, err := url.Parse(plugin.Src)
+		if err != nil || u.Scheme != "" || u.Host != "" {
+			continue
+		}
+		dir := path.Dir(path.Clean(strings.TrimPrefix(u.Path, "/")))
+		if strings.Contains(dir, "/") && !strings.HasPrefix(dir, "../") {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// containsPath returns true if the content has the path not as a part of a longer path such as 'a.png' in 'a.png.bak'.
+func containsPath(content string, p string) bool {
+	for offset := 0; ; {
+		i := strings.Index(content[offset:], p)
+		if i < 0 {
+			return false
+		}
+		start, end := offset+i, offset+i+len(p)
+		before := start == 0 || content[start-1] == '/' || !isPathChar(content[start-1])
+		// The period may end the sentence such as 'See assets/a.png.'
+		after := end == len(content) || !isPathChar(content[end]) || content[end] == '.' && (end+1 == len(content) || !isPathChar(content[end+1]))
+		if before && after {
+			return true
+		}
+		offset = start + 1
+	}
+}
+
+// cssReferences returns the relative URLs in url() and @import of the CSS.
+func cssReferences(css string) []string {
+	refs := make([]string, 0)
+	matches := append(cssURLRegexp.FindAllStringSubmatch(css, -1), cssImportRegexp.FindAllStringSubmatch(css, -1)...)
+	for _, match := range matches {
+		u, err := url.Parse(strings.TrimSpace(match[1]))
+		if err != nil || u.Scheme != "" || u.Host != "" || strings.HasPrefix(u.Path, "/") || u.Path == "" {
+			continue
+		}
+		refs = append(refs, u.Path)
+	}
+	return refs
+}
+
+// isReferencingFile returns true if the file may reference the other files.
+func isReferencingFile(p string) bool {
+	switch path.Ext(p) {
+	case ".css", ".js", ".html", ".htm", ".json", ".svg", ".md":
+		return true
+	}
+	return false
+}
@@ -6,9 +6,10 @@ import (
 	"html"
 	"io"
 	"io/fs"
-	"log"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 
@@ -20,8 +21,16 @@ type RevealJS struct {
 	dataDirectory string
 	EmbedHTML     bool
 	EmbedMarkdown bool
-	fs            fs.FS
-	userFS        fs.FS
+	// IncludeDrafts shows the draft slides with the DRAFT badge, otherwise the draft slides are excluded.
+	IncludeDrafts bool
+	// Force rewrites all the files in the build, otherwise the files not changed since the previous build are reused.
+	Force       bool
+	fs          fs.FS
+	userFS      fs.FS
+	profileName string
+	overrides   []ConfigOverride
+	// slidesErr is the error of 'slides' in config.yml found by ReloadConfig.
+	slidesErr error
 }
 
 func NewRevealJS(dataDirectory string) (*RevealJS, error) {
@@ -35,7 +44,12 @@ func NewRevealJS(dataDirectory string) (*RevealJS, error) {
 	userFS := NewSlideResourceFS(os.DirFS(absDataDir))
 	systemFS := vfs.NewMergeFS(defaultFS(), revealjsFS())
 	mfs := vfs.NewMergeFS(userFS, systemFS)
-	revealJS := &RevealJS{nil, absDataDir, true, false, mfs, userFS}
+	revealJS := &RevealJS{
+		dataDirectory: absDataDir,
+		EmbedHTML:     true,
+		fs:            mfs,
+		userFS:        userFS,
+	}
 	if err := revealJS.ReloadConfig(); err != nil {
 		return nil, err
 	}
@@ -43,36 +57,63 @@ func NewRevealJS(dataDirectory string) (*RevealJS, error) {
 }
 
 func (r *RevealJS) ReloadConfig() error {
-	configFile, err := r.fs.Open(FileNameConfig)
+	c, err := r.loadConfigFile()
 	if err != nil {
 		return err
 	}
-	c, err := LoadConfigFile(configFile)
-	defer configFile.Close()
-	if err != nil {
+	// The profile may select the slide files
+	if err := r.applyProfile(c); err != nil {
 		return err
 	}
 	r.config = c
-	if files, collectErr := r.collectSlideSourceFiles(); collectErr != nil {
-		return collectErr
-	} else {
-		for _, file := range files {
-			if IsMarkdown(file) {
-				b, err := fs.ReadFile(r.fs, file)
-				if err != nil {
-					return err
-				}
-				configInMd, err := LoadConfigFromMarkdown(string(b))
-				if err != nil {
-					return err
-				}
-				c.OverrideWith(configInMd)
+	// The broken 'slides' is reported by CheckLinks and SlidesError,
+	// and the front matter of the files matching the valid entries is loaded
+	files, err := r.collectSlideSourceFiles()
+	slidesErr := errors.Join(err, r.slidePatternsError())
+	// Reload not to apply the profile twice, which may append the values
+	if c, err = r.loadConfigFile(); err != nil {
+		return err
+	}
+	for _, file := range files {
+		if IsMarkdown(file) {
+			b, err := fs.ReadFile(r.fs, file)
+			if err != nil {
+				return err
+			}
+			configInMd, err := LoadConfigFromMarkdown(string(b))
+			if err != nil {
+				return fmt.Errorf("failed to load front matter of %s: %w", file, err)
 			}
+			configInMd.setSource(file)
+			c.OverrideWith(configInMd)
 		}
 	}
+	// The profile takes precedence over the front matter
+	if err := r.applyProfile(c); err != nil {
+		return err
+	}
+	if err := c.applyOverrides(r.overrides); err != nil {
+		return err
+	}
+	r.config = c
+	r.slidesErr = slidesErr
 	return nil
 }
 
+// SlidesError returns the error of the broken entries of 'slides' in config.yml found when the config is loaded.
+// The broken entries are skipped in the deck, and the front matter of the slide files not matched is not applied to the config.
+func (r *RevealJS) SlidesError() error {
+	return r.slidesErr
+}
+
+// loadConfigFile loads config.yml in the data directory, or the default config if not exist.
+func (r *RevealJS) loadConfigFile() (*Config, error) {
+	if path := filepath.Join(r.dataDirectory, FileNameConfig); exist(path) {
+		return LoadConfigFilePath(path)
+	}
+	return loadDefaultConfig()
+}
+
 type HTMLGeneratorParams struct {
 	HotReload bool
 	Revision  *string
@@ -109,191 +150,229 @@ setInterval(function() {
 	} else {
 		hotReloadScript = ""
 	}
-	sections, err := r.generateSections()
+	// The served slides are not relative to the base URL
+	var baseURL string
+	if !params.HotReload {
+		baseURL = r.config.BaseURL
+	}
+	deck, err := r.Deck()
 	if err != nil {
 		return err
 	}
 	if err := tmpl.Execute(w, map[string]interface{}{
 		"config":          r.config,
-		"sections":        sections,
+		"deck":            deck,
+		"sections":        r.renderSections(deck),
+		"hasDrafts":       deck.hasDrafts(),
 		"hotReloadScript": hotReloadScript,
+		"baseURL":         baseURL,
 	}); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (r *RevealJS) generateSections() ([]string, error) {
-	files, err := r.collectSlideSourceFiles()
-	if err != nil {
-		return nil, err
+// The files matching the valid entries of 'slides' are returned, and the broken entries are reported by SlidesError,
+// so that a stale entry doesn't break the whole deck.
+func (r *RevealJS) collectSlideSourceFiles() ([]string, error) {
+	if r.config.Slides != nil && len(r.config.Slides) > 0 {
+		files, _ := r.expandSlidePatterns(r.config.Slides)
+		return files, nil
 	}
-	return r.doGenerateSections(files), nil
+	return r.slideFilesIn(".")
 }
 
-func (r *RevealJS) collectSlideSourceFiles() ([]string, error) {
-	if r.config.Slides != nil && len(r.config.Slides) > 0 {
-		return r.config.Slides, nil
+// slidePatternsError returns the errors of the broken entries of 'slides' joined, or nil if no entry is broken.
+func (r *RevealJS) slidePatternsError() error {
+	_, errs := r.expandSlidePatterns(r.config.Slides)
+	joined := make([]error, 0, len(errs))
+	for _, err := range errs {
+		joined = append(joined, err)
 	}
+	return errors.Join(joined...)
+}
 
+// slideFilesIn returns the slide files in the directory tree.
+func (r *RevealJS) slideFilesIn(dir string) ([]string, error) {
 	files := make([]string, 0)
-	fs.WalkDir(r.userFS, ".", func(path string, d fs.DirEntry, err error) error {
-		if d.IsDir() {
-			return nil
-		}
+	err := walkFilesSorted(r.userFS, dir, r.slideFileOrder, func(path string) {
 		if IsHTML(path) || IsMarkdown(path) {
 			files = append(files, path)
 		}
-		return nil
 	})
-	return files, nil
+	return files, err
 }
 
-func (r *RevealJS) doGenerateSections(files []string) []string {
-	sections := make([]string, 0)
-	for _, file := range files {
-		section, err := r.sectionFor(file)
-		if err != nil {
-			log.Printf("failed to generate <section> tag for %s: %s", file, err)
-		} else {
-			sections = append(sections, section)
+// expandSlidePatterns expands the entries of 'slides' in config.yml to the slide files.
+// Each entry is a file, a directory, or a glob pattern such as 'slides/intro/*.md'.
+// Entries starting with '!' exclude the files matched so far, such as '!slides/draft-*'.
+// The broken entries are skipped and returned as the errors.
+func (r *RevealJS) expandSlidePatterns(patterns []string) ([]string, []*SlidePatternError) {
+	files := make([]string, 0)
+	added := make(map[string]bool)
+	errs := make([]*SlidePatternError, 0)
+	for _, pattern := range patterns {
+		if exclude, ok := strings.CutPrefix(pattern, "!"); ok {
+			if _, err := path.Match(exclude, ""); err != nil {
+				errs = append(errs, &SlidePatternError{pattern, fmt.Sprintf("invalid pattern '%s' in slides: %s", pattern, err)})
+				continue
+			}
+			filtered := make([]string, 0, len(files))
+			for _, file := range files {
+				if matchSlidePattern(exclude, file) {
+					delete(added, file)
+				} else {
+					filtered = append(filtered, file)
+				}
+			}
+			files = filtered
+			continue
 		}
-	}
-	return sections
-}
 
-func (r *RevealJS) sectionFor(relPathFromDataDirectory string) (string, error) {
-	path := filepath.Join(r.dataDirectory, relPathFromDataDirectory)
-
-	if IsHTML(path) {
-		if r.EmbedHTML {
-			content, err := os.ReadFile(path)
-			if err != nil {
-				return "", fmt.Errorf("failed to load file %s: %w", path, err)
-			}
-			return string(content), nil
+		matches, err := r.matchSlideFiles(pattern)
+		if err != nil {
+			errs = append(errs, &SlidePatternError{pattern, fmt.Sprintf("invalid pattern '%s' in slides: %s", pattern, err)})
+			continue
 		}
-		return fmt.Sprintf(`<section data-external="%s"></section>`, relPathFromDataDirectory), nil
-	} else if IsMarkdown(path) {
-		if r.EmbedMarkdown {
-			b, err := os.ReadFile(path)
-			if err != nil {
-				log.Printf("failed to read markdown file: %s", path)
+		if len(matches) == 0 {
+			errs = append(errs, &SlidePatternError{pattern, fmt.Sprintf("no slide files match '%s' in slides", pattern)})
+			continue
+		}
+		for _, file := range matches {
+			if !added[file] {
+				added[file] = true
+				files = append(files, file)
 			}
-			md := NewMarkdown(string(b)).WithoutYAMLHeader()
-			return fmt.Sprintf(`<section data-markdown data-separator="^\r?\n---\r?\n$" data-separator-vertical="^\r?\n~~~\r?\n$">%s</section>`, html.EscapeString(md)), nil
 		}
-		return fmt.Sprintf(`<section data-markdown="%s" data-separator="^\r?\n---\r?\n$" data-separator-vertical="^\r?\n~~~\r?\n$"></section>`, relPathFromDataDirectory), nil
-	} else {
-		return "", fmt.Errorf("unsupported slide file: %s", path)
 	}
+	return files, errs
 }
 
-func (r *RevealJS) DataDirectory() string {
-	return r.dataDirectory
+// SlidePatternError is the error of an entry of 'slides' in config.yml.
+type SlidePatternError struct {
+	Pattern string
+	message string
 }
 
-func (r *RevealJS) FileSystem() fs.FS {
-	return r.fs
+func (e *SlidePatternError) Error() string {
+	return e.message
 }
 
-func (r *RevealJS) Build(dst string) error {
-	// Make 'build' directory if not exist
-	if err := os.MkdirAll(dst, 0700); err != nil {
-		return err
-	}
-
-	// generate index.html
-	f, err := os.Create(filepath.Join(dst, FileNameIndexHTML))
+// matchSlideFiles returns the slide files matching the pattern.
+func (r *RevealJS) matchSlideFiles(pattern string) ([]string, error) {
+	pattern = path.Clean(filepath.ToSlash(pattern))
+	matches, err := fs.Glob(r.userFS, pattern)
 	if err != nil {
-		return err
-	}
-	if err := r.GenerateIndexHTML(f, &HTMLGeneratorParams{
-		HotReload: false,
-		Revision:  nil,
-	}); err != nil {
-		return err
+		return nil, err
 	}
-	defer f.Close()
+	sort.SliceStable(matches, func(i, j int) bool { return naturalLess(matches[i], matches[j]) })
 
-	// copy fs files
-	return extractFile(r.fs, ".", dst, func(path string) bool {
-		// Skip paths under dst directory
-		absSrc := filepath.Join(r.dataDirectory, path)
-		if strings.HasPrefix(absSrc, dst) {
-			return true
+	files := make([]string, 0)
+	for _, match := range matches {
+		info, err := fs.Stat(r.userFS, match)
+		if err != nil {
+			return nil, err
 		}
-
-		// Skip index.html.tmpl and config.yml
-		filename := filepath.Base(path)
-		if filename == FileNameIndexHTMLTmpl || filename == FileNameConfig {
-			return true
+		if info.IsDir() {
+			dirFiles, err := r.slideFilesIn(match)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, dirFiles...)
+		} else if IsHTML(match) || IsMarkdown(match) {
+			files = append(files, match)
 		}
+	}
+	return files, nil
+}
 
-		// Skip embedded html/md files
-		if r.EmbedHTML && IsHTML(filename) {
-			return true
-		}
-		if r.EmbedMarkdown && IsMarkdown(filename) {
+// matchSlidePattern returns true if the file or one of its parent directories matches the pattern.
+func matchSlidePattern(pattern string, file string) bool {
+	pattern = path.Clean(filepath.ToSlash(pattern))
+	for p := file; p != "." && p != "/"; p = path.Dir(p) {
+		if matched, _ := path.Match(pattern, p); matched {
 			return true
 		}
+	}
+	return false
+}
 
-		return false
-	})
+// slideFileOrder returns the 'order' in the front matter of the markdown file, or 0 if not specified.
+// The files with smaller order come first in the same directory.
+func (r *RevealJS) slideFileOrder(path string, entry fs.DirEntry) int {
+	if entry.IsDir() || !IsMarkdown(path) {
+		return 0
+	}
+	b, err := fs.ReadFile(r.userFS, path)
+	if err != nil {
+		return 0
+	}
+	header, err := NewMarkdown(string(b)).YAMLHeader()
+	if err != nil {
+		return 0
+	}
+	return parseSlideFrontMatter(header).Order
 }
 
-// extractFile copies files from src to dst.
-// src is a path from the root of the file system
-// dst is a path of the local file system
-func extractFile(fileSystem fs.FS, src, dst string, skip func(path string) bool) error {
-	return fs.WalkDir(fileSystem, src, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-		if skip(path) {
-			return nil
-		}
+// SlideFiles returns the slide files in the order they are shown.
+func (r *RevealJS) SlideFiles() ([]string, error) {
+	return r.collectSlideSourceFiles()
+}
+
+// renderSections renders the sources of the deck to the top level <section> tags.
+func (r *RevealJS) renderSections(deck *Deck) []string {
+	sections := make([]string, 0, len(deck.Sources))
+	for _, source := range deck.Sources {
+		sections = append(sections, r.renderSection(source))
+	}
+	return sections
+}
 
-		relPath, _ := filepath.Rel(src, path)
-		copyDst := filepath.Join(dst, relPath)
-		if d.IsDir() {
-			return nil
+func (r *RevealJS) renderSection(source *SlideSource) string {
+	switch {
+	case source.File == "":
+		// Generated source
+		return source.Content
+	case source.Kind == SlideKindStack:
+		var sb strings.Builder
+		sb.WriteString("<section>\n")
+		for _, child := range source.Children {
+			sb.WriteString(r.renderStackedSection(child))
+			sb.WriteString("\n")
 		}
-		parentDir := filepath.Dir(copyDst)
-		if !exist(parentDir) {
-			if err := os.MkdirAll(parentDir, 0700); err != nil {
-				return err
-			}
+		sb.WriteString("</section>")
+		return sb.String()
+	case source.Kind == SlideKindHTML:
+		if r.EmbedHTML {
+			return source.Content
 		}
-
-		var reader io.ReadCloser
-		reader, err = fileSystem.Open(path)
-		if err != nil {
-			return err
+		return fmt.Sprintf(`<section data-external="%s"></section>`, source.File)
+	default:
+		if r.EmbedMarkdown {
+			return fmt.Sprintf(`<section data-markdown data-separator="%s" data-separator-vertical="%s"%s>%s</section>`, markdownSeparator, markdownVerticalSeparator, draftAttributeOf(source), html.EscapeString(source.Content))
 		}
-		defer reader.Close()
+		return fmt.Sprintf(`<section data-markdown="%s" data-separator="%s" data-separator-vertical="%s"%s></section>`, source.File, markdownSeparator, markdownVerticalSeparator, draftAttributeOf(source))
+	}
+}
 
-		writer, err := os.Create(copyDst)
-		if err != nil {
-			return err
-		}
-		defer writer.Close()
+// renderStackedSection renders the file in a vertical stack.
+// The markdown is split by the both separators so that all the slides are stacked vertically.
+func (r *RevealJS) renderStackedSection(source *SlideSource) string {
+	if source.Kind != SlideKindMarkdown {
+		return r.renderSection(source)
+	}
+	if r.EmbedMarkdown {
+		return fmt.Sprintf(`<section data-markdown data-separator="%s"%s>%s</section>`, markdownStackSeparator, draftAttributeOf(source), html.EscapeString(source.Content))
+	}
+	return fmt.Sprintf(`<section data-markdown="%s" data-separator="%s"%s></section>`, source.File, markdownStackSeparator, draftAttributeOf(source))
+}
 
-		if IsMarkdown(path) {
-			b, err := io.ReadAll(reader)
-			if err != nil {
-				return err
-			}
-			content := NewMarkdown(string(b)).WithoutYAMLHeader()
-			_, err = writer.WriteString(content)
-			if err != nil {
-				return err
-			}
-		} else {
-			_, err = io.Copy(writer, reader)
-		}
-		return err
-	})
+func (r *RevealJS) DataDirectory() string {
+	return r.dataDirectory
+}
+
+func (r *RevealJS) FileSystem() fs.FS {
+	return r.fs
 }
 
 func exist(path string) bool {
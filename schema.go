@@ -0,0 +1,203 @@
+package revealjs
+
+import (
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// JSONSchemaDraft is the version of the generated JSON Schema, supported by the YAML language servers.
+const JSONSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// jsonSchemaProvider is implemented by the types whose JSON Schema can't be derived from the Go type.
+type jsonSchemaProvider interface {
+	jsonSchema() map[string]interface{}
+}
+
+// fieldSchema returns the JSON Schema of the config field whose Go type is too loose, or nil.
+func fieldSchema(name string) map[string]interface{} {
+	switch name {
+	case "RevealJS":
+		return revealJSSchema()
+	case "InternalPlugins":
+		return pluginsSchema()
+	case "Profiles":
+		return profilesSchema()
+	}
+	return nil
+}
+
+// ConfigJSONSchema returns the JSON Schema of config.yml derived from Config.
+func ConfigJSONSchema() map[string]interface{} {
+	schema := structSchema(reflect.TypeOf(Config{}))
+	schema["$schema"] = JSONSchemaDraft
+	schema["title"] = "revealcli config.yml"
+	return schema
+}
+
+// FrontMatterJSONSchema returns the JSON Schema of the front matter of the markdown slide files,
+// derived from Config and SlideFrontMatter.
+func FrontMatterJSONSchema() map[string]interface{} {
+	schema := structSchema(reflect.TypeOf(Config{}), "Extends")
+	for name, property := range structSchema(reflect.TypeOf(SlideFrontMatter{}))["properties"].(map[string]interface{}) {
+		schema["properties"].(map[string]interface{})[name] = property
+	}
+	// The front matter may have other keys for the slides
+	schema["additionalProperties"] = true
+	schema["$schema"] = JSONSchemaDraft
+	schema["title"] = "revealcli front matter"
+	return schema
+}
+
+// typeSchema derives the JSON Schema from the Go type.
+// All the values are nullable since `null` unsets the value in the base config.
+func typeSchema(t reflect.Type) map[string]interface{} {
+	if provider, ok := reflect.Zero(t).Interface().(jsonSchemaProvider); ok {
+		return provider.jsonSchema()
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return typeSchema(t.Elem())
+	case reflect.String:
+		return nullable("string")
+	case reflect.Bool:
+		return nullable("boolean")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return nullable("integer")
+	case reflect.Float32, reflect.Float64:
+		return nullable("number")
+	case reflect.Slice:
+		schema := nullable("array")
+		schema["items"] = typeSchema(t.Elem())
+		return schema
+	case reflect.Map:
+		schema := nullable("object")
+		schema["additionalProperties"] = typeSchema(t.Elem())
+		return schema
+	case reflect.Struct:
+		return structSchema(t)
+	}
+	return map[string]interface{}{}
+}
+
+// structSchema derives the JSON Schema of the struct from the yaml tags of the fields except the excluded fields.
+func structSchema(t reflect.Type, exclude ...string) map[string]interface{} {
+	properties := map[string]interface{}{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || slices.Contains(exclude, field.Name) {
+			continue
+		}
+		name, options, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if strings.Contains(options, "inline") {
+			for k, v := range structSchema(field.Type, exclude...)["properties"].(map[string]interface{}) {
+				properties[k] = v
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		if schema := fieldSchema(field.Name); schema != nil {
+			properties[name] = schema
+		} else {
+			properties[name] = typeSchema(field.Type)
+		}
+	}
+	schema := nullable("object")
+	schema["properties"] = properties
+	schema["additionalProperties"] = false
+	return schema
+}
+
+// revealJSSchema returns the JSON Schema of the reveal.js config.
+// The keys in the default config are listed with their default values, and the keys in properties are validated.
+func revealJSSchema() map[string]interface{} {
+	schemas := map[string]interface{}{}
+	defaults := map[string]interface{}{}
+	if c, err := loadDefaultConfig(); err == nil {
+		defaults = c.RevealJS
+	}
+	for k, v := range defaults {
+		schema := map[string]interface{}{}
+		if p := configProperty(k); p != nil {
+			schema = p.jsonSchema()
+		}
+		schema["default"] = v
+		schemas[k] = schema
+	}
+	for k, p := range properties {
+		if _, ok := schemas[k]; !ok {
+			schemas[k] = p.jsonSchema()
+		}
+	}
+	schema := nullable("object")
+	schema["properties"] = schemas
+	// Any reveal.js config can be given
+	schema["additionalProperties"] = true
+	return schema
+}
+
+func pluginsSchema() map[string]interface{} {
+	names := make([]string, 0, len(builtinPlugins))
+	for name := range builtinPlugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	plugin := structSchema(reflect.TypeOf(Plugin{}))
+	plugin["type"] = "object"
+	plugin["required"] = []string{"name"}
+
+	schema := nullable("array")
+	schema["items"] = map[string]interface{}{
+		"anyOf": []interface{}{
+			map[string]interface{}{"enum": names},
+			plugin,
+		},
+	}
+	return schema
+}
+
+func profilesSchema() map[string]interface{} {
+	schema := nullable("object")
+	// The profiles can't be nested
+	schema["additionalProperties"] = structSchema(reflect.TypeOf(Profile{}), "Extends", "Profiles")
+	return schema
+}
+
+func nullable(typ string) map[string]interface{} {
+	return map[string]interface{}{"type": []string{typ, "null"}}
+}
+
+func (t SlideTags) jsonSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"anyOf": []interface{}{
+			nullable("string"),
+			typeSchema(reflect.TypeOf([]string{})),
+		},
+	}
+}
+
+func (p *StringProperty) jsonSchema() map[string]interface{} {
+	if len(p.validValues) == 0 {
+		return nullable("string")
+	}
+	values := make([]interface{}, 0, len(p.validValues)+1)
+	for _, v := range p.validValues {
+		values = append(values, v)
+	}
+	return map[string]interface{}{"enum": append(values, nil)}
+}
+
+func (p *BoolProperty) jsonSchema() map[string]interface{} {
+	return nullable("boolean")
+}
+
+func (p *NumberProperty) jsonSchema() map[string]interface{} {
+	return nullable("number")
+}
+
+func (p *JSONProperty) jsonSchema() map[string]interface{} {
+	return map[string]interface{}{}
+}
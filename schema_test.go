@@ -0,0 +1,62 @@
+package revealjs
+
+import (
+	"testing"
+)
+
+func schemaProperties(t *testing.T, schema map[string]interface{}, path ...string) map[string]interface{} {
+	t.Helper()
+	for _, name := range path {
+		property, ok := schema["properties"].(map[string]interface{})[name]
+		if !ok {
+			t.Fatalf("property %s not found", name)
+		}
+		schema = property.(map[string]interface{})
+	}
+	return schema["properties"].(map[string]interface{})
+}
+
+func TestConfigJSONSchema(t *testing.T) {
+	schema := ConfigJSONSchema()
+	assertEqual(t, schema["$schema"], JSONSchemaDraft)
+
+	properties := schemaProperties(t, schema)
+	for _, name := range []string{"extends", "slides", "title", "theme", "revealjs", "plugins", "agenda", "profiles"} {
+		if _, ok := properties[name]; !ok {
+			t.Errorf("property %s not found", name)
+		}
+	}
+
+	defaults, err := loadDefaultConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	revealJS := schemaProperties(t, schema, "revealjs")
+	for k, v := range defaults.RevealJS {
+		assertEqual(t, revealJS[k].(map[string]interface{})["default"], v)
+	}
+	assertEqual(t, revealJS["controlsLayout"].(map[string]interface{})["enum"], []interface{}{"bottom-right", "edges", nil})
+
+	profile := properties["profiles"].(map[string]interface{})["additionalProperties"].(map[string]interface{})
+	profileProperties := profile["properties"].(map[string]interface{})
+	for _, name := range []string{"tags", "excludeTags", "title"} {
+		if _, ok := profileProperties[name]; !ok {
+			t.Errorf("property %s not found in the profile", name)
+		}
+	}
+	if _, ok := profileProperties["profiles"]; ok {
+		t.Error("profiles should not be nested")
+	}
+}
+
+func TestFrontMatterJSONSchema(t *testing.T) {
+	properties := schemaProperties(t, FrontMatterJSONSchema())
+	for _, name := range []string{"order", "draft", "tags", "title", "revealjs"} {
+		if _, ok := properties[name]; !ok {
+			t.Errorf("property %s not found", name)
+		}
+	}
+	if _, ok := properties["extends"]; ok {
+		t.Error("extends is not supported in the front matter")
+	}
+}
@@ -2,81 +2,167 @@ package revealjs
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"log"
 	"net/http"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 )
 
 type Server struct {
 	port     int
 	revealJS *RevealJS
+	// mu guards revealJS, which the watcher reloads while the handlers read it.
+	mu sync.RWMutex
+	// Clock returns the current time used for the revisions and the modification time of the generated files.
+	Clock func() time.Time
+	// Watcher reloads the slides when the data directory is updated, created by Watch if nil.
+	Watcher *Watcher
 }
 
 func NewServer(port int, revealJS *RevealJS) *Server {
-	return &Server{port, revealJS}
+	return &Server{port: port, revealJS: revealJS, Clock: time.Now}
 }
 
+// Start watches the data directory and serves the slides on the port in the background.
 func (s *Server) Start() error {
-	watcher, err := NewWatcher(s.revealJS.DataDirectory(), func() {
-		// User may change config.yml. Reload it.
-		s.revealJS.ReloadConfig()
-	})
-	if err != nil {
+	if err := s.Watch(); err != nil {
 		return err
 	}
+	s.logSlidesError()
 	go func() {
-		http.HandleFunc("/revision", func(w http.ResponseWriter, req *http.Request) {
-			w.Header().Set("Content-Type", "text/plain")
-			w.Write([]byte(watcher.Revision.Value))
-		})
+		log.Printf("Start server on http://localhost:%d", s.port)
+		err := http.ListenAndServe(fmt.Sprintf(":%d", s.port), s.Handler())
+		if err != nil {
+			log.Fatal("Failed to start server: ", err)
+		}
+	}()
+	return nil
+}
 
-		http.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
-			// Is index.html
-			if req.URL.Path == "/" {
-				// Generate index.html
-				buf := &bytes.Buffer{}
-				if err := s.revealJS.GenerateIndexHTML(buf, &HTMLGeneratorParams{
-					HotReload: true,
-					Revision:  &watcher.Revision.Value,
-				}); err != nil {
-					log.Println(err)
-					http.Error(w, "failed to generate index.html", http.StatusInternalServerError)
-					return
-				}
-				http.ServeContent(w, req, "index.html", time.Now(), bytes.NewReader(buf.Bytes()))
+// Watch starts the watcher of the data directory, which is stopped by Close.
+func (s *Server) Watch() error {
+	if s.Watcher == nil {
+		watcher, err := NewWatcherWithClock(s.revealJS.DataDirectory(), func() {
+			// User may change config.yml. Reload it.
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if err := s.revealJS.ReloadConfig(); err != nil {
+				log.Println("Failed to reload config: ", err)
 				return
 			}
+			s.logSlidesError()
+		}, s.Clock)
+		if err != nil {
+			return err
+		}
+		s.Watcher = watcher
+	}
+	return s.Watcher.Start()
+}
 
-			// If the file is markdown, remove the yaml header.
-			if IsMarkdown(req.URL.Path) {
-				file, err := s.revealJS.FileSystem().Open(req.URL.Path[1:]) // remove '/'
-				if err != nil {
-					log.Println(err)
-					http.Error(w, "failed to open file", http.StatusInternalServerError)
-					return
-				}
-				defer file.Close()
-				b, err := io.ReadAll(file)
-				if err != nil {
-					http.Error(w, "failed to read file", http.StatusInternalServerError)
-				}
-				content := NewMarkdown(string(b)).WithoutYAMLHeader()
-				http.ServeContent(w, req, req.URL.Path, time.Now(), strings.NewReader(content))
+// logSlidesError logs the broken entries of 'slides' in config.yml, which are skipped in the served slides.
+func (s *Server) logSlidesError() {
+	if err := s.revealJS.SlidesError(); err != nil {
+		log.Println("Skipped the broken slides in config.yml: ", err)
+	}
+}
+
+// Close stops the watcher of the data directory.
+func (s *Server) Close() error {
+	if s.Watcher == nil {
+		return nil
+	}
+	return s.Watcher.Close()
+}
+
+// Handler returns the handler serving the slides with the hot reload, and the revision of the watcher at '/revision'.
+// Watch must be called before serving.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/revision", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(s.Watcher.Revision.String()))
+	})
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+
+		// Is index.html
+		if req.URL.Path == "/" {
+			// Generate index.html
+			buf := &bytes.Buffer{}
+			revision := s.Watcher.Revision.String()
+			if err := s.revealJS.GenerateIndexHTML(buf, &HTMLGeneratorParams{
+				HotReload: true,
+				Revision:  &revision,
+			}); err != nil {
+				log.Println(err)
+				http.Error(w, "failed to generate index.html", http.StatusInternalServerError)
 				return
 			}
+			http.ServeContent(w, req, "index.html", s.Clock(), bytes.NewReader(buf.Bytes()))
+			return
+		}
 
-			// Other files
-			http.ServeFileFS(w, req, s.revealJS.FileSystem(), req.URL.Path)
-		})
-		log.Printf("Start server on http://localhost:%d", s.port)
-		err := http.ListenAndServe(fmt.Sprintf(":%d", s.port), nil)
-		if err != nil {
-			log.Fatal("Failed to start server: ", err)
+		// If the file is markdown, remove the yaml header.
+		if IsMarkdown(req.URL.Path) {
+			name := req.URL.Path[1:] // remove '/'
+			// The slide files are served as in the deck, without the slides excluded by the drafts and the profile
+			deck, err := s.revealJS.Deck()
+			if err != nil {
+				log.Println(err)
+				http.Error(w, "failed to load slides", http.StatusInternalServerError)
+				return
+			}
+			if source := deck.sourceOf(name); source != nil {
+				http.ServeContent(w, req, req.URL.Path, s.Clock(), strings.NewReader(source.Content))
+				return
+			}
+			files, err := s.revealJS.SlideFiles()
+			if err != nil {
+				log.Println(err)
+				http.Error(w, "failed to load slides", http.StatusInternalServerError)
+				return
+			}
+			if slices.Contains(files, name) {
+				// All the slides in the file are excluded
+				http.NotFound(w, req)
+				return
+			}
+
+			file, err := s.revealJS.FileSystem().Open(name)
+			if errors.Is(err, fs.ErrNotExist) {
+				http.NotFound(w, req)
+				return
+			}
+			if err != nil {
+				log.Println(err)
+				http.Error(w, "failed to open file", http.StatusInternalServerError)
+				return
+			}
+			defer file.Close()
+			b, err := io.ReadAll(file)
+			if err != nil {
+				http.Error(w, "failed to read file", http.StatusInternalServerError)
+				return
+			}
+			content := NewMarkdown(string(b)).WithoutYAMLHeader()
+			if s.revealJS.IncludeDrafts {
+				content = markHiddenMarkdownSlides(content)
+			}
+			http.ServeContent(w, req, req.URL.Path, s.Clock(), strings.NewReader(content))
+			return
 		}
-	}()
-	go watcher.Start()
-	return nil
+
+		// Other files
+		http.ServeFileFS(w, req, s.revealJS.FileSystem(), req.URL.Path)
+	})
+	return mux
 }
@@ -0,0 +1,74 @@
+package revealjs
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Run with -race to detect the config reloaded while the slides are served.
+func TestServerReloadsConfigWhileServing(t *testing.T) {
+	dataDir := t.TempDir()
+	writeFile(t, filepath.Join(dataDir, "slides.md"), "# Slide\n")
+	writeFile(t, filepath.Join(dataDir, FileNameConfig), "title: Title 0\n")
+	r, err := NewRevealJS(dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := NewServer(0, r)
+	if err := server.Watch(); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	tmpDir := t.TempDir()
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 1; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			tmp := filepath.Join(tmpDir, FileNameConfig)
+			if err := os.WriteFile(tmp, []byte(fmt.Sprintf("title: Title %d\n", i)), 0644); err != nil {
+				t.Error(err)
+				return
+			}
+			if err := os.Rename(tmp, filepath.Join(dataDir, FileNameConfig)); err != nil {
+				t.Error(err)
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		resp, err := http.Get(httpServer.URL + "/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("/ responded %d: %s", resp.StatusCode, b)
+		}
+	}
+	close(done)
+	wg.Wait()
+}
@@ -0,0 +1,48 @@
+package revealjs
+
+import (
+	"strings"
+)
+
+// naturalLess reports whether a is less than b comparing the runs of digits as numbers,
+// so that "2.md" comes before "10.md".
+func naturalLess(a, b string) bool {
+	for a != "" && b != "" {
+		aDigits, bDigits := isDigit(a[0]), isDigit(b[0])
+		if aDigits != bDigits {
+			return a < b
+		}
+		var aChunk, bChunk string
+		aChunk, a = nextChunk(a, aDigits)
+		bChunk, b = nextChunk(b, bDigits)
+		if aChunk == bChunk {
+			continue
+		}
+		if !aDigits {
+			return aChunk < bChunk
+		}
+		aNumber, bNumber := strings.TrimLeft(aChunk, "0"), strings.TrimLeft(bChunk, "0")
+		if len(aNumber) != len(bNumber) {
+			return len(aNumber) < len(bNumber)
+		}
+		if aNumber != bNumber {
+			return aNumber < bNumber
+		}
+		// Same number with different leading zeros
+		return len(aChunk) < len(bChunk)
+	}
+	return len(a) < len(b)
+}
+
+// nextChunk splits s into the leading run of digits (or non-digits) and the rest.
+func nextChunk(s string, digits bool) (string, string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) == digits {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
@@ -0,0 +1,197 @@
+package revealjs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SourceDefault is the source of the values in the default config.
+const SourceDefault = "default"
+
+// setSource sets the source of all the values in the config, such as the path of the config file.
+func (c *Config) setSource(source string) {
+	for key := range c.sources {
+		c.sources[key] = source
+	}
+	for name, profile := range c.Profiles {
+		if profile != nil {
+			profile.setSource(fmt.Sprintf("%s (profile '%s')", source, name))
+		}
+	}
+}
+
+// overrideSources records the sources of the values overridden by other.
+func (c *Config) overrideSources(other *Config) {
+	if c.sources == nil {
+		c.sources = map[string]string{}
+	}
+	for key, source := range other.sources {
+		if s := other.merge[key]; s == mergeReplace || s == mergeUnset {
+			for k := range c.sources {
+				if strings.HasPrefix(k, key+".") {
+					delete(c.sources, k)
+				}
+			}
+		}
+		c.sources[key] = source
+	}
+}
+
+// configKeys returns the paths of the keys in the YAML node such as 'revealjs' and 'revealjs.controls'.
+func configKeys(node *yaml.Node, path string) []string {
+	keys := make([]string, 0)
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			keys = append(keys, configKeys(child, path)...)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if path != "" {
+				key = path + "." + key
+			}
+			keys = append(keys, key)
+			keys = append(keys, configKeys(node.Content[i+1], key)...)
+		}
+	}
+	return keys
+}
+
+// Source returns where the config value of the key such as 'revealjs.controls' is set,
+// which is the path of the config file, the markdown file, the option or the environment variable,
+// SourceDefault for the default config, or empty string if the value is not set.
+func (c *Config) Source(key string) string {
+	for {
+		if source, ok := c.sources[key]; ok {
+			return source
+		}
+		i := strings.LastIndex(key, ".")
+		if i < 0 {
+			return ""
+		}
+		key = key[:i]
+	}
+}
+
+// Get returns the config value of the key such as 'revealjs.controls'.
+func (c *Config) Get(key string) (interface{}, bool) {
+	m, err := c.toMap()
+	if err != nil {
+		return nil, false
+	}
+	var v interface{} = m
+	for _, k := range strings.Split(key, ".") {
+		parent, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if v, ok = parent[k]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+func (c *Config) toMap() (map[string]interface{}, error) {
+	b, err := yaml.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]interface{}{}
+	if err := yaml.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ConfigSource returns where the effective config value of the key is set.
+// The paths of the files are relative to the data directory.
+func (r *RevealJS) ConfigSource(key string) string {
+	source := r.config.Source(key)
+	if filepath.IsAbs(source) {
+		if rel, err := filepath.Rel(r.dataDirectory, source); err == nil {
+			return rel
+		}
+	}
+	return source
+}
+
+// ConfigValue returns the effective config value of the key such as 'revealjs.controls'.
+func (r *RevealJS) ConfigValue(key string) (interface{}, bool) {
+	return r.config.Get(key)
+}
+
+// WriteConfig writes the effective config with the source of each value in the format (yaml|json).
+func (r *RevealJS) WriteConfig(w io.Writer, format string) error {
+	switch format {
+	case "yaml":
+		var node yaml.Node
+		if err := node.Encode(r.config); err != nil {
+			return err
+		}
+		r.commentSources(&node, "")
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		defer encoder.Close()
+		return encoder.Encode(&node)
+	case "json":
+		m, err := r.config.toMap()
+		if err != nil {
+			return err
+		}
+		sources := map[string]string{}
+		r.collectSources(m, "", sources)
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(map[string]interface{}{
+			"config":  m,
+			"sources": sources,
+		})
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// commentSources adds the sources of the values as the line comments.
+func (r *RevealJS) commentSources(node *yaml.Node, path string) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		p := key.Value
+		if path != "" {
+			p = path + "." + key.Value
+		}
+		if value.Kind == yaml.MappingNode && len(value.Content) > 0 {
+			r.commentSources(value, p)
+			continue
+		}
+		if source := r.ConfigSource(p); source != "" {
+			key.LineComment = source
+		}
+	}
+}
+
+func (r *RevealJS) collectSources(m map[string]interface{}, path string, sources map[string]string) {
+	for k, v := range m {
+		p := k
+		if path != "" {
+			p = path + "." + k
+		}
+		if child, ok := v.(map[string]interface{}); ok && len(child) > 0 {
+			r.collectSources(child, p, sources)
+			continue
+		}
+		if source := r.ConfigSource(p); source != "" {
+			sources[p] = source
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package runner
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+// SectionAsserter asserts a slide, the section element in index.html.
+type SectionAsserter struct {
+	Node *html.Node
+	// name is the position of the section such as '1' or '1-2' used in the messages.
+	name string
+}
+
+// SectionCount asserts the number of the horizontal slides.
+func (a *IndexHTMLAsserter) SectionCount(t *testing.T, expected int) {
+	t.Helper()
+	if actual := len(a.sections(t)); actual != expected {
+		t.Errorf("expected %d sections but %d", expected, actual)
+	}
+}
+
+// Section returns the i-th horizontal slide starting from 0.
+func (a *IndexHTMLAsserter) Section(t *testing.T, i int) *SectionAsserter {
+	t.Helper()
+	sections := a.sections(t)
+	if i < 0 || i >= len(sections) {
+		t.Fatalf("section %d not found in %d sections", i, len(sections))
+	}
+	return &SectionAsserter{sections[i], fmt.Sprint(i)}
+}
+
+func (a *IndexHTMLAsserter) sections(t *testing.T) []*html.Node {
+	t.Helper()
+	slides := findNode(a.doc, func(n *html.Node) bool {
+		return n.Type == html.ElementNode && n.Data == "div" && hasClass(n, "slides")
+	})
+	if slides == nil {
+		t.Fatalf("<div class=\"slides\"> not found in index.html")
+	}
+	return childSections(slides)
+}
+
+// RevealConfig returns the object given to Reveal.initialize.
+// See jsParser for the types of the values.
+func (a *IndexHTMLAsserter) RevealConfig(t *testing.T) map[string]interface{} {
+	t.Helper()
+	const initialize = "Reveal.initialize("
+	script := findNode(a.doc, func(n *html.Node) bool {
+		return n.Type == html.ElementNode && n.Data == "script" && strings.Contains(textContent(n), initialize)
+	})
+	if script == nil {
+		t.Fatalf("Reveal.initialize not found in index.html")
+	}
+	js := textContent(script)
+	v, err := parseJSValue(js[strings.Index(js, initialize)+len(initialize):])
+	if err != nil {
+		t.Fatalf("failed to parse Reveal.initialize: %s", err)
+	}
+	config, ok := v.(map[string]interface{})
+	if !ok {
+		t.Fatalf("Reveal.initialize is not called with an object: %v", v)
+	}
+	return config
+}
+
+// Plugins returns the names of the plugins given to Reveal.initialize such as 'RevealMarkdown'.
+func (a *IndexHTMLAsserter) Plugins(t *testing.T) []string {
+	t.Helper()
+	plugins, ok := a.RevealConfig(t)["plugins"].([]interface{})
+	if !ok {
+		t.Fatalf("plugins not found in Reveal.initialize")
+	}
+	names := make([]string, 0, len(plugins))
+	for _, plugin := range plugins {
+		names = append(names, fmt.Sprint(plugin))
+	}
+	return names
+}
+
+// HasAttr asserts the section has the attribute with the value.
+func (a *SectionAsserter) HasAttr(t *testing.T, key, value string) {
+	t.Helper()
+	actual, ok := attr(a.Node, key)
+	if !ok {
+		t.Errorf("section %s doesn't have attribute %s", a.name, key)
+	} else if actual != value {
+		t.Errorf("section %s has attribute %s=%q but expected %q", a.name, key, actual, value)
+	}
+}
+
+// NotHasAttr asserts the section doesn't have the attribute.
+func (a *SectionAsserter) NotHasAttr(t *testing.T, key string) {
+	t.Helper()
+	if actual, ok := attr(a.Node, key); ok {
+		t.Errorf("section %s has attribute %s=%q", a.name, key, actual)
+	}
+}
+
+// HasText asserts the text of the section contains s ignoring the indents.
+func (a *SectionAsserter) HasText(t *testing.T, s string) {
+	t.Helper()
+	text := normalizeText(textContent(a.Node))
+	if !strings.Contains(text, normalizeText(s)) {
+		t.Errorf("text %q not found in section %s: %q", s, a.name, text)
+	}
+}
+
+// SectionCount asserts the number of the vertical slides in the section.
+func (a *SectionAsserter) SectionCount(t *testing.T, expected int) {
+	t.Helper()
+	if actual := len(childSections(a.Node)); actual != expected {
+		t.Errorf("expected %d sections in section %s but %d", expected, a.name, actual)
+	}
+}
+
+// Section returns the i-th vertical slide in the section starting from 0.
+func (a *SectionAsserter) Section(t *testing.T, i int) *SectionAsserter {
+	t.Helper()
+	sections := childSections(a.Node)
+	if i < 0 || i >= len(sections) {
+		t.Fatalf("section %s-%d not found in %d sections", a.name, i, len(sections))
+	}
+	return &SectionAsserter{sections[i], fmt.Sprintf("%s-%d", a.name, i)}
+}
+
+func childSections(n *html.Node) []*html.Node {
+	sections := make([]*html.Node, 0)
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.Data == "section" {
+			sections = append(sections, c)
+		}
+	}
+	return sections
+}
+
+func findNode(n *html.Node, match func(n *html.Node) bool) *html.Node {
+	if match(n) {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findNode(c, match); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func attr(n *html.Node, key string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+func hasClass(n *html.Node, class string) bool {
+	v, _ := attr(n, "class")
+	for _, c := range strings.Fields(v) {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+func textContent(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return sb.String()
+}
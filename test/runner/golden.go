@@ -0,0 +1,255 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/uphy/go-revealjs"
+)
+
+// DirNameGolden is the directory of the expected build output beside testdata.
+const DirNameGolden = "golden"
+
+// diffContext is the number of the unchanged lines shown around the changes in the diffs.
+const diffContext = 3
+
+// matchesGolden compares the build output with the golden directory beside testdata, and reports the differences as unified diffs.
+// It is called after the checks of the tests having the golden directory. Create an empty golden directory to start comparing.
+// The reveal.js files copied from the embedded reveal.js are not compared unless testdata has them.
+// Run the tests with -update to save the build output as the golden directory.
+func (r *BuildResultAsserter) matchesGolden(t *testing.T) {
+	t.Helper()
+	actual, err := r.goldenFiles()
+	if err != nil {
+		t.Fatalf("failed to read the build output: %s", err)
+	}
+	goldenDir := filepath.Join(r.testDir, DirNameGolden)
+	if *update {
+		if err := os.RemoveAll(goldenDir); err != nil {
+			t.Fatal(err)
+		}
+		for _, path := range actual {
+			dst := filepath.Join(goldenDir, filepath.FromSlash(path))
+			b, err := os.ReadFile(filepath.Join(r.Dir, filepath.FromSlash(path)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(dst, b, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return
+	}
+
+	expected, err := listFiles(goldenDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actualSet := map[string]bool{}
+	for _, path := range actual {
+		actualSet[path] = true
+	}
+	for _, path := range expected {
+		if !actualSet[path] {
+			t.Errorf("file %s not found in the build output, run with -update to remove it from %s", path, goldenDir)
+		}
+		delete(actualSet, path)
+	}
+	for _, path := range actual {
+		if actualSet[path] {
+			t.Errorf("file %s not found in %s, run with -update to add it", path, goldenDir)
+			continue
+		}
+		expectedBytes, err := os.ReadFile(filepath.Join(goldenDir, filepath.FromSlash(path)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		actualBytes, err := os.ReadFile(filepath.Join(r.Dir, filepath.FromSlash(path)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Equal(expectedBytes, actualBytes) {
+			continue
+		}
+		if !isText(expectedBytes) || !isText(actualBytes) {
+			t.Errorf("binary file %s is different from the golden file", path)
+			continue
+		}
+		t.Errorf("file %s is different from the golden file, run with -update to update it:\n%s", path, unifiedDiff(path, string(expectedBytes), string(actualBytes)))
+	}
+}
+
+// goldenFiles returns the slash separated paths of the build output to compare with the golden directory.
+func (r *BuildResultAsserter) goldenFiles() ([]string, error) {
+	files, err := listFiles(r.Dir)
+	if err != nil {
+		return nil, err
+	}
+	// The original paths of the fingerprinted files
+	originals := map[string]string{}
+	if b, err := os.ReadFile(filepath.Join(r.Dir, revealjs.FileNameManifest)); err == nil {
+		var manifest revealjs.Manifest
+		if err := json.Unmarshal(b, &manifest); err != nil {
+			return nil, err
+		}
+		for _, f := range manifest.Files {
+			if f.Original != "" {
+				originals[f.Path] = f.Original
+			}
+		}
+	}
+	dataDir := filepath.Join(r.testDir, "testdata")
+	compared := make([]string, 0, len(files))
+	for _, path := range files {
+		if path == revealjs.FileNameManifest {
+			continue
+		}
+		original := path
+		if o, ok := originals[path]; ok {
+			original = o
+		}
+		if strings.HasPrefix(original, "dist/") || strings.HasPrefix(original, "plugin/") {
+			if _, err := os.Stat(filepath.Join(dataDir, filepath.FromSlash(original))); err != nil {
+				continue
+			}
+		}
+		compared = append(compared, path)
+	}
+	return compared, nil
+}
+
+// listFiles returns the sorted slash separated paths of the files in the directory.
+func listFiles(dir string) ([]string, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+	files := make([]string, 0)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func isText(b []byte) bool {
+	return utf8.Valid(b) && bytes.IndexByte(b, 0) < 0
+}
+
+// unifiedDiff returns the differences of the lines in the unified format.
+func unifiedDiff(name string, expected string, actual string) string {
+	a := splitLines(expected)
+	b := splitLines(actual)
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	type edit struct {
+		op   byte
+		line string
+		// ai and bi are the line indices in a and b before the edit.
+		ai, bi int
+	}
+	edits := make([]edit, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			edits = append(edits, edit{' ', a[i], i, j})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, edit{'-', a[i], i, j})
+			i++
+		default:
+			edits = append(edits, edit{'+', b[j], i, j})
+			j++
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- golden/%s\n+++ %s\n", name, name)
+	for start := 0; start < len(edits); {
+		// Find the next change and the hunk around it
+		for start < len(edits) && edits[start].op == ' ' {
+			start++
+		}
+		if start == len(edits) {
+			break
+		}
+		from := max(start-diffContext, 0)
+		end := start
+		for k := start; k < len(edits); k++ {
+			if edits[k].op != ' ' {
+				end = k + 1
+			} else if k-end >= 2*diffContext {
+				break
+			}
+		}
+		to := min(end+diffContext, len(edits))
+		aCount, bCount := 0, 0
+		for _, e := range edits[from:to] {
+			if e.op != '+' {
+				aCount++
+			}
+			if e.op != '-' {
+				bCount++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(edits[from].ai, aCount), hunkRange(edits[from].bi, bCount))
+		for _, e := range edits[from:to] {
+			fmt.Fprintf(&sb, "%c%s\n", e.op, e.line)
+		}
+		start = to
+	}
+	return sb.String()
+}
+
+func hunkRange(start int, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
@@ -0,0 +1,38 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	expected := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	actual := "1\n2\n3\nfour\n5\n6\n7\n8\n9\n10\n11\n12\n13\n"
+	diff := unifiedDiff("index.html", expected, actual)
+	want := strings.Join([]string{
+		"--- golden/index.html",
+		"+++ index.html",
+		"@@ -1,7 +1,7 @@",
+		" 1",
+		" 2",
+		" 3",
+		"-4",
+		"+four",
+		" 5",
+		" 6",
+		" 7",
+		"@@ -10,3 +10,4 @@",
+		" 10",
+		" 11",
+		" 12",
+		"+13",
+		"",
+	}, "\n")
+	if diff != want {
+		t.Errorf("unexpected diff:\n%s\nexpected:\n%s", diff, want)
+	}
+
+	if diff := unifiedDiff("a.txt", "", "x\n"); diff != "--- golden/a.txt\n+++ a.txt\n@@ -0,0 +1,1 @@\n+x\n" {
+		t.Errorf("unexpected diff:\n%s", diff)
+	}
+}
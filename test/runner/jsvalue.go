@@ -0,0 +1,220 @@
+package runner
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expr is a JavaScript expression in the reveal.js config which is not a literal,
+// such as the plugin 'RevealMarkdown' or 'Number.POSITIVE_INFINITY'.
+type Expr string
+
+// jsParser parses the JavaScript object literal given to Reveal.initialize.
+// The objects are parsed to map[string]interface{}, the arrays to []interface{}, the numbers to float64,
+// the strings to string, true and false to bool, null to nil, and the other expressions to Expr.
+type jsParser struct {
+	s   string
+	pos int
+}
+
+func parseJSValue(s string) (interface{}, error) {
+	p := &jsParser{s: s}
+	return p.value()
+}
+
+func (p *jsParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%s at %d: %q", fmt.Sprintf(format, args...), p.pos, p.s[p.pos:min(p.pos+20, len(p.s))])
+}
+
+// skipSpaces skips the white spaces and the comments.
+func (p *jsParser) skipSpaces() {
+	for p.pos < len(p.s) {
+		switch {
+		case unicode.IsSpace(rune(p.s[p.pos])):
+			p.pos++
+		case strings.HasPrefix(p.s[p.pos:], "//"):
+			if i := strings.IndexByte(p.s[p.pos:], '\n'); i >= 0 {
+				p.pos += i
+			} else {
+				p.pos = len(p.s)
+			}
+		case strings.HasPrefix(p.s[p.pos:], "/*"):
+			if i := strings.Index(p.s[p.pos+2:], "*/"); i >= 0 {
+				p.pos += i + 4
+			} else {
+				p.pos = len(p.s)
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *jsParser) value() (interface{}, error) {
+	p.skipSpaces()
+	if p.pos >= len(p.s) {
+		return nil, p.errorf("unexpected end")
+	}
+	switch c := p.s[p.pos]; {
+	case c == '{':
+		return p.object()
+	case c == '[':
+		return p.array()
+	case c == '\'' || c == '"':
+		return p.string()
+	case c == '-' || c == '.' || ('0' <= c && c <= '9'):
+		return p.number()
+	}
+	expr := p.expr()
+	switch expr {
+	case "":
+		return nil, p.errorf("unexpected character")
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	return Expr(expr), nil
+}
+
+func (p *jsParser) object() (interface{}, error) {
+	p.pos++
+	m := map[string]interface{}{}
+	for {
+		p.skipSpaces()
+		if p.pos < len(p.s) && p.s[p.pos] == '}' {
+			p.pos++
+			return m, nil
+		}
+		var key string
+		if p.pos < len(p.s) && (p.s[p.pos] == '\'' || p.s[p.pos] == '"') {
+			k, err := p.string()
+			if err != nil {
+				return nil, err
+			}
+			key = k.(string)
+		} else {
+			key = p.expr()
+		}
+		if key == "" {
+			return nil, p.errorf("expected key")
+		}
+		p.skipSpaces()
+		if p.pos >= len(p.s) || p.s[p.pos] != ':' {
+			return nil, p.errorf("expected ':'")
+		}
+		p.pos++
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		m[key] = v
+		if err := p.separator('}'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *jsParser) array() (interface{}, error) {
+	p.pos++
+	a := make([]interface{}, 0)
+	for {
+		p.skipSpaces()
+		if p.pos < len(p.s) && p.s[p.pos] == ']' {
+			p.pos++
+			return a, nil
+		}
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, v)
+		if err := p.separator(']'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// separator skips ',' between the items, or stops before the end allowing the trailing comma.
+func (p *jsParser) separator(end byte) error {
+	p.skipSpaces()
+	if p.pos < len(p.s) && p.s[p.pos] == ',' {
+		p.pos++
+		return nil
+	}
+	if p.pos < len(p.s) && p.s[p.pos] == end {
+		return nil
+	}
+	return p.errorf("expected ',' or '%c'", end)
+}
+
+func (p *jsParser) string() (interface{}, error) {
+	quote := p.s[p.pos]
+	p.pos++
+	var sb strings.Builder
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		switch {
+		case c == quote:
+			p.pos++
+			return sb.String(), nil
+		case c == '\\' && p.pos+1 < len(p.s):
+			p.pos++
+			switch e := p.s[p.pos]; e {
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case 'u':
+				if p.pos+5 > len(p.s) {
+					return nil, p.errorf("invalid escape")
+				}
+				r, err := strconv.ParseUint(p.s[p.pos+1:p.pos+5], 16, 16)
+				if err != nil {
+					return nil, p.errorf("invalid escape")
+				}
+				sb.WriteRune(rune(r))
+				p.pos += 4
+			default:
+				sb.WriteByte(e)
+			}
+			p.pos++
+		default:
+			sb.WriteByte(c)
+			p.pos++
+		}
+	}
+	return nil, p.errorf("unterminated string")
+}
+
+func (p *jsParser) number() (interface{}, error) {
+	start := p.pos
+	for p.pos < len(p.s) && strings.IndexByte("+-.0123456789eE", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+	f, err := strconv.ParseFloat(p.s[start:p.pos], 64)
+	if err != nil {
+		p.pos = start
+		return nil, p.errorf("invalid number")
+	}
+	return f, nil
+}
+
+// expr reads the identifiers joined by '.' such as 'Number.POSITIVE_INFINITY'.
+func (p *jsParser) expr() string {
+	start := p.pos
+	for p.pos < len(p.s) {
+		c := rune(p.s[p.pos])
+		if c != '_' && c != '$' && c != '.' && !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			break
+		}
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
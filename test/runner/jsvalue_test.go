@@ -0,0 +1,47 @@
+package runner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseJSValue(t *testing.T) {
+	v, err := parseJSValue(`{
+		// Display controls in the bottom right corner
+		controls: true,
+		transition: 'slide', /* none/fade/slide */
+		'quoted': "it\'s A",
+		width: 960,
+		margin: 0.04,
+		pdfMaxPagesPerSlide: Number.POSITIVE_INFINITY,
+		autoSlide: null,
+		keyboard: {"13": 'next'},
+		plugins: [
+			RevealMarkdown,
+			RevealZoom,
+		],
+	});`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"controls":            true,
+		"transition":          "slide",
+		"quoted":              "it's A",
+		"width":               960.0,
+		"margin":              0.04,
+		"pdfMaxPagesPerSlide": Expr("Number.POSITIVE_INFINITY"),
+		"autoSlide":           nil,
+		"keyboard":            map[string]interface{}{"13": "next"},
+		"plugins":             []interface{}{Expr("RevealMarkdown"), Expr("RevealZoom")},
+	}
+	if !reflect.DeepEqual(v, expected) {
+		t.Errorf("expected %v but %v", expected, v)
+	}
+
+	for _, invalid := range []string{`{controls true}`, `{controls: }`, `['unterminated]`, `[1 2]`} {
+		if _, err := parseJSValue(invalid); err == nil {
+			t.Errorf("expected error for %s", invalid)
+		}
+	}
+}
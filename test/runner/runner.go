@@ -1,6 +1,8 @@
 package runner
 
 import (
+	"bytes"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -8,50 +10,92 @@ import (
 	"testing"
 
 	"github.com/uphy/go-revealjs"
+	"golang.org/x/net/html"
 )
 
+// update saves the build output to the golden directories and the screenshots of the tests, instead of comparing with them.
+var update = flag.Bool("update", false, "update the golden directories and the screenshots of the tests")
+
 type (
 	BuildResultAsserter struct {
 		Dir string
+		// testDir is the directory of the test case containing testdata.
+		testDir  string
+		revealJS *revealjs.RevealJS
 	}
 	IndexHTMLAsserter struct {
 		HTML string
+		doc  *html.Node
 	}
 )
 
 func Run(t *testing.T, check func(asserter *BuildResultAsserter)) {
+	RunWithProfile(t, "", check)
+}
+
+// RunWithProfile builds the slides with the profile defined in testdata/config.yml.
+func RunWithProfile(t *testing.T, profile string, check func(asserter *BuildResultAsserter)) {
+	run(t, func(r *revealjs.RevealJS) error {
+		if profile == "" {
+			return nil
+		}
+		return r.UseProfile(profile)
+	}, check)
+}
+
+// RunWithOverrides builds the slides with the config overrides such as 'revealjs.controls=false'.
+func RunWithOverrides(t *testing.T, overrides []string, check func(asserter *BuildResultAsserter)) {
+	run(t, func(r *revealjs.RevealJS) error {
+		parsed := make([]revealjs.ConfigOverride, 0, len(overrides))
+		for _, s := range overrides {
+			override, err := revealjs.ParseConfigOverride(s)
+			if err != nil {
+				return err
+			}
+			parsed = append(parsed, override)
+		}
+		return r.OverrideConfig(parsed...)
+	}, check)
+}
+
+func run(t *testing.T, configure func(r *revealjs.RevealJS) error, check func(asserter *BuildResultAsserter)) {
 	wd, err := os.Getwd()
 	if err != nil {
-		t.Errorf("failed to get working directory: %s", err)
+		t.Fatalf("failed to get working directory: %s", err)
+	}
+	dir, err := os.MkdirTemp("", fmt.Sprintf("revealjs-test-%s-*", filepath.Base(wd)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	r, err := build(wd, dir, configure)
+	if err != nil {
+		t.Fatalf("failed to build %s: %s", wd, err)
+	}
+	// The check runs in the goroutine of t, so that it can stop or skip the test with t
+	asserter := &BuildResultAsserter{Dir: dir, testDir: wd, revealJS: r}
+	check(asserter)
+	if info, err := os.Stat(filepath.Join(wd, DirNameGolden)); err == nil && info.IsDir() {
+		asserter.matchesGolden(t)
 	}
-	t.Run(wd, func(t *testing.T) {
-		if err := build(wd, check); err != nil {
-			t.Log(err)
-			t.Fail()
-		}
-	})
 }
 
-func build(wd string, check func(result *BuildResultAsserter)) error {
-	testName := filepath.Base(wd)
+func build(wd string, dir string, configure func(r *revealjs.RevealJS) error) (*revealjs.RevealJS, error) {
 	dataDir := filepath.Join(wd, "testdata")
 	r, err := revealjs.NewRevealJS(dataDir)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	r.EmbedHTML = true
 	r.EmbedMarkdown = true
-
-	dir, err := os.MkdirTemp("", fmt.Sprintf("revealjs-test-%s-*", testName))
-	if err != nil {
-		return err
+	r.IncludeDrafts = false
+	if err := configure(r); err != nil {
+		return nil, err
 	}
-	defer os.RemoveAll(dir)
 	if err := r.Build(dir); err != nil {
-		return err
+		return nil, err
 	}
-	check(&BuildResultAsserter{Dir: dir})
-	return nil
+	return r, nil
 }
 
 func (r *BuildResultAsserter) HasRevealJSFiles(t *testing.T) {
@@ -92,10 +136,13 @@ func (r *BuildResultAsserter) IndexHTML(t *testing.T) *IndexHTMLAsserter {
 	indexHTML := filepath.Join(r.Dir, "index.html")
 	b, err := os.ReadFile(indexHTML)
 	if err != nil {
-		t.Errorf("failed to read index.html: %s", err)
+		t.Fatalf("failed to read index.html: %s", err)
 	}
-	html := normalizeText(string(b))
-	return &IndexHTMLAsserter{HTML: html}
+	doc, err := html.Parse(bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("failed to parse index.html: %s", err)
+	}
+	return &IndexHTMLAsserter{HTML: normalizeText(string(b)), doc: doc}
 }
 
 func normalizeText(s string) string {
@@ -114,6 +161,13 @@ func (a *IndexHTMLAsserter) HasString(t *testing.T, s string) {
 	}
 }
 
+func (a *IndexHTMLAsserter) NotHasString(t *testing.T, s string) {
+	if strings.Contains(a.HTML, normalizeText(s)) {
+		t.Errorf("string %s found", s)
+		t.Errorf("index.html: %s", a.HTML)
+	}
+}
+
 func (a *IndexHTMLAsserter) HasTitle(t *testing.T, title string) {
 	a.HasString(t, fmt.Sprintf("<title>%s</title>", title))
 }
@@ -0,0 +1,242 @@
+package runner
+
+import (
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/uphy/go-revealjs"
+)
+
+const (
+	// reloadTimeout is the time to wait for the server to notice the updated files.
+	reloadTimeout = 5 * time.Second
+	// reloadSettleTime is the time the revision stays unchanged after the reload,
+	// so that the events of the same update don't change it later.
+	reloadSettleTime = 200 * time.Millisecond
+)
+
+// ServerAsserter asserts the slides served by the dev server with the hot reload.
+type ServerAsserter struct {
+	// Dir is the data directory copied from testdata, which the test can edit.
+	Dir      string
+	server   *httptest.Server
+	revision string
+}
+
+// Serve copies testdata to a temporary directory, and starts the dev server of the directory.
+func Serve(t *testing.T, check func(asserter *ServerAsserter)) {
+	ServeWithProfile(t, "", check)
+}
+
+// ServeWithProfile starts the dev server with the profile defined in testdata/config.yml.
+func ServeWithProfile(t *testing.T, profile string, check func(asserter *ServerAsserter)) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get working directory: %s", err)
+	}
+	dataDir := t.TempDir()
+	if err := copyDir(filepath.Join(wd, "testdata"), dataDir); err != nil {
+		t.Fatalf("failed to copy testdata: %s", err)
+	}
+	r, err := revealjs.NewRevealJS(dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile != "" {
+		if err := r.UseProfile(profile); err != nil {
+			t.Fatal(err)
+		}
+	}
+	server := revealjs.NewServer(0, r)
+	server.Clock = newFakeClock()
+	if err := server.Watch(); err != nil {
+		t.Fatalf("failed to watch %s: %s", dataDir, err)
+	}
+	defer server.Close()
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	asserter := &ServerAsserter{Dir: dataDir, server: httpServer}
+	asserter.revision = asserter.Revision(t)
+	check(asserter)
+}
+
+// newFakeClock returns the clock advancing a second every call, so that every update makes a new revision.
+func newFakeClock() func() time.Time {
+	var mu sync.Mutex
+	now := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		now = now.Add(time.Second)
+		return now
+	}
+}
+
+// Get requests the path to the server and returns the status code and the body.
+func (a *ServerAsserter) Get(t *testing.T, path string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(a.server.URL + path)
+	if err != nil {
+		t.Fatalf("failed to get %s: %s", path, err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read %s: %s", path, err)
+	}
+	return resp.StatusCode, string(b)
+}
+
+// Revision returns the current revision served at '/revision'.
+func (a *ServerAsserter) Revision(t *testing.T) string {
+	t.Helper()
+	status, body := a.Get(t, "/revision")
+	if status != http.StatusOK {
+		t.Fatalf("failed to get the revision: %d %s", status, body)
+	}
+	return body
+}
+
+// HasContent asserts the path is served with the content containing s.
+func (a *ServerAsserter) HasContent(t *testing.T, path string, s string) {
+	t.Helper()
+	status, body := a.Get(t, path)
+	if status != http.StatusOK {
+		t.Errorf("%s responded %d: %s", path, status, body)
+	} else if !strings.Contains(body, s) {
+		t.Errorf("string %q not found in %s: %s", s, path, body)
+	}
+}
+
+// HasRevealJSFile asserts the path is served with the file of the reveal.js embedded in the binary,
+// such as '/dist/reveal.js'.
+func (a *ServerAsserter) HasRevealJSFile(t *testing.T, path string) {
+	t.Helper()
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		t.Fatal("failed to find the source of the runner")
+	}
+	// The reveal.js embedded from the module root
+	expected, err := os.ReadFile(filepath.Join(filepath.Dir(file), "..", "..", "assets", "reveal.js", filepath.FromSlash(path)))
+	if err != nil {
+		t.Fatalf("failed to read the embedded file of %s: %s", path, err)
+	}
+	if len(expected) == 0 {
+		t.Fatalf("embedded file of %s is empty", path)
+	}
+	status, body := a.Get(t, path)
+	if status != http.StatusOK {
+		t.Errorf("%s responded %d: %s", path, status, body)
+	} else if body != string(expected) {
+		t.Errorf("%s is different from the embedded file", path)
+	}
+}
+
+// NotHasContent asserts the path is served with the content not containing s.
+func (a *ServerAsserter) NotHasContent(t *testing.T, path string, s string) {
+	t.Helper()
+	status, body := a.Get(t, path)
+	if status != http.StatusOK {
+		t.Errorf("%s responded %d: %s", path, status, body)
+	} else if strings.Contains(body, s) {
+		t.Errorf("string %q found in %s: %s", s, path, body)
+	}
+}
+
+// NotFound asserts the path responds 404.
+func (a *ServerAsserter) NotFound(t *testing.T, path string) {
+	t.Helper()
+	if status, body := a.Get(t, path); status != http.StatusNotFound {
+		t.Errorf("%s responded %d instead of 404: %s", path, status, body)
+	}
+}
+
+// WriteFile writes the file in the data directory, and waits for the server to reload it.
+// The file is replaced by renaming, so that the server never reads the file partially written.
+func (a *ServerAsserter) WriteFile(t *testing.T, name string, content string) {
+	t.Helper()
+	path := filepath.Join(a.Dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(a.Dir), ".revealjs-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		t.Fatal(err)
+	}
+	if err := tmp.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		t.Fatal(err)
+	}
+	a.WaitForReload(t)
+}
+
+// RemoveFile removes the file in the data directory, and waits for the server to reload it.
+func (a *ServerAsserter) RemoveFile(t *testing.T, name string) {
+	t.Helper()
+	if err := os.Remove(filepath.Join(a.Dir, filepath.FromSlash(name))); err != nil {
+		t.Fatal(err)
+	}
+	a.WaitForReload(t)
+}
+
+// WaitForReload asserts the revision changes since the previous reload, and waits until it settles.
+func (a *ServerAsserter) WaitForReload(t *testing.T) {
+	t.Helper()
+	deadline := time.Now().Add(reloadTimeout)
+	revision := a.Revision(t)
+	for revision == a.revision {
+		if time.Now().After(deadline) {
+			t.Fatalf("revision %s not changed in %s", revision, reloadTimeout)
+		}
+		time.Sleep(10 * time.Millisecond)
+		revision = a.Revision(t)
+	}
+	for {
+		time.Sleep(reloadSettleTime)
+		settled := a.Revision(t)
+		if settled == revision {
+			break
+		}
+		revision = settled
+	}
+	a.revision = revision
+}
+
+// copyDir copies the files in the directory tree src to dst.
+func copyDir(src string, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, b, 0644)
+	})
+}
@@ -0,0 +1,188 @@
+package runner
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/uphy/go-revealjs"
+)
+
+// DirNameScreenshots is the directory of the golden screenshots of the slides beside testdata.
+const DirNameScreenshots = "screenshots"
+
+const (
+	// pixelTolerance is the difference of a color channel ignored as the anti-aliasing.
+	pixelTolerance = 16
+	// maxDiffRatio is the ratio of the different pixels allowed in a screenshot.
+	maxDiffRatio = 0.001
+)
+
+// MatchesScreenshots renders each slide in the headless Chromium and compares it with the golden screenshot
+// in the screenshots directory beside testdata, such as 'screenshots/slide-0-0.png'.
+// Run the tests with -update to save the screenshots as the golden screenshots.
+// The screenshots not matched are saved as '*.actual.png' and '*.diff.png' next to the golden screenshots.
+// The test is skipped if Chromium is not found, or the screenshots directory doesn't exist without -update.
+func (r *BuildResultAsserter) MatchesScreenshots(t *testing.T) {
+	t.Helper()
+	chromium, err := revealjs.FindChromium()
+	if err != nil {
+		t.Skipf("skipped the screenshots: %s", err)
+	}
+	deck, err := r.revealJS.Deck()
+	if err != nil {
+		t.Fatalf("failed to load the deck: %s", err)
+	}
+	width, height := r.revealJS.SlideSize()
+
+	goldenDir := filepath.Join(r.testDir, DirNameScreenshots)
+	if *update {
+		if err := os.RemoveAll(goldenDir); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(goldenDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	} else if _, err := os.Stat(goldenDir); os.IsNotExist(err) {
+		t.Skipf("skipped the screenshots: %s not found, run with -update to create it", goldenDir)
+	}
+	indexHTML, err := filepath.Abs(filepath.Join(r.Dir, revealjs.FileNameIndexHTML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := map[string]bool{}
+	for _, slide := range deck.AllSlides() {
+		name := fmt.Sprintf("slide-%d-%d.png", slide.H, slide.V)
+		names[name] = true
+		golden := filepath.Join(goldenDir, name)
+		actual := strings.TrimSuffix(golden, ".png") + ".actual.png"
+		diff := strings.TrimSuffix(golden, ".png") + ".diff.png"
+		os.Remove(actual)
+		os.Remove(diff)
+
+		// Disable the transitions and controls so that the screenshots are stable.
+		url := fmt.Sprintf("file://%s?transition=none&controls=false&progress=false&slideNumber=false#/%d/%d", filepath.ToSlash(indexHTML), slide.H, slide.V)
+		target := actual
+		if *update {
+			target = golden
+		}
+		if err := chromium.Screenshot(url, target, width, height); err != nil {
+			t.Errorf("failed to take screenshot of slide %s: %s", slide.Number(), err)
+			continue
+		}
+		if *update {
+			continue
+		}
+		if err := compareScreenshots(golden, actual, diff); err != nil {
+			t.Errorf("slide %s doesn't match %s: %s", slide.Number(), golden, err)
+			continue
+		}
+		os.Remove(actual)
+	}
+
+	if *update {
+		return
+	}
+	// The slides removed from the deck
+	entries, err := os.ReadDir(goldenDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasSuffix(name, ".actual.png") || strings.HasSuffix(name, ".diff.png") {
+			continue
+		}
+		if !names[name] {
+			t.Errorf("slide of %s not found in the deck, run with -update to remove it", filepath.Join(goldenDir, name))
+		}
+	}
+}
+
+// compareScreenshots compares the screenshot with the golden screenshot, and writes the different pixels to diff if not matched.
+func compareScreenshots(golden string, actual string, diff string) error {
+	expectedImage, err := readPNG(golden)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("golden screenshot not found, run with -update to create it")
+	}
+	if err != nil {
+		return err
+	}
+	actualImage, err := readPNG(actual)
+	if err != nil {
+		return err
+	}
+	diffImage, n := diffImages(expectedImage, actualImage)
+	if diffImage == nil {
+		return fmt.Errorf("size %v is different from %v, see %s", actualImage.Bounds().Size(), expectedImage.Bounds().Size(), actual)
+	}
+	bounds := expectedImage.Bounds()
+	if ratio := float64(n) / float64(bounds.Dx()*bounds.Dy()); ratio > maxDiffRatio {
+		if err := writePNG(diff, diffImage); err != nil {
+			return err
+		}
+		return fmt.Errorf("%d pixels (%.2f%%) are different, see %s and %s", n, ratio*100, actual, diff)
+	}
+	return nil
+}
+
+// diffImages returns the image highlighting the different pixels in red and the number of them,
+// or nil if the sizes are different.
+func diffImages(expected image.Image, actual image.Image) (*image.RGBA, int) {
+	bounds := expected.Bounds()
+	if bounds.Size() != actual.Bounds().Size() {
+		return nil, 0
+	}
+	offset := actual.Bounds().Min.Sub(bounds.Min)
+	diff := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	n := 0
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			e := color.RGBAModel.Convert(expected.At(x, y)).(color.RGBA)
+			a := color.RGBAModel.Convert(actual.At(x+offset.X, y+offset.Y)).(color.RGBA)
+			p := image.Pt(x-bounds.Min.X, y-bounds.Min.Y)
+			if channelDiff(e.R, a.R) > pixelTolerance || channelDiff(e.G, a.G) > pixelTolerance || channelDiff(e.B, a.B) > pixelTolerance || channelDiff(e.A, a.A) > pixelTolerance {
+				diff.Set(p.X, p.Y, color.RGBA{0xff, 0, 0, 0xff})
+				n++
+			} else {
+				// Fade the same pixels to show the different pixels clearly
+				gray := uint8((uint16(e.R) + uint16(e.G) + uint16(e.B)) / 3 / 4)
+				diff.Set(p.X, p.Y, color.RGBA{0xc0 + gray, 0xc0 + gray, 0xc0 + gray, 0xff})
+			}
+		}
+	}
+	return diff, n
+}
+
+func channelDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+func readPNG(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
+
+func writePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
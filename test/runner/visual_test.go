@@ -0,0 +1,157 @@
+package runner
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/uphy/go-revealjs"
+)
+
+func filledImage(width int, height int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestCompareScreenshots(t *testing.T) {
+	dir := t.TempDir()
+	golden := filepath.Join(dir, "slide-0-0.png")
+	actual := filepath.Join(dir, "slide-0-0.actual.png")
+	diff := filepath.Join(dir, "slide-0-0.diff.png")
+	if err := compareScreenshots(golden, actual, diff); err == nil {
+		t.Error("expected error for the missing golden screenshot")
+	}
+
+	white := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	if err := writePNG(golden, filledImage(100, 100, white)); err != nil {
+		t.Fatal(err)
+	}
+	// The anti-aliasing is tolerated
+	img := filledImage(100, 100, white)
+	img.Set(0, 0, color.RGBA{0xf8, 0xf8, 0xf8, 0xff})
+	if err := writePNG(actual, img); err != nil {
+		t.Fatal(err)
+	}
+	if err := compareScreenshots(golden, actual, diff); err != nil {
+		t.Errorf("expected to match: %s", err)
+	}
+
+	for y := 0; y < 20; y++ {
+		img.Set(50, y, color.RGBA{0, 0, 0, 0xff})
+	}
+	if err := writePNG(actual, img); err != nil {
+		t.Fatal(err)
+	}
+	if err := compareScreenshots(golden, actual, diff); err == nil {
+		t.Error("expected error for the different pixels")
+	}
+	diffImage, err := readPNG(diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := color.RGBAModel.Convert(diffImage.At(50, 0)); c != (color.RGBA{0xff, 0, 0, 0xff}) {
+		t.Errorf("expected the different pixel in red, but got %v", c)
+	}
+
+	if err := writePNG(actual, filledImage(100, 50, white)); err != nil {
+		t.Fatal(err)
+	}
+	if err := compareScreenshots(golden, actual, diff); err == nil {
+		t.Error("expected error for the different size")
+	}
+}
+
+// TestCompareScreenshotFiles compares the fixed screenshots in testdata/screenshots.
+func TestCompareScreenshotFiles(t *testing.T) {
+	dir := filepath.Join("testdata", "screenshots")
+	golden := filepath.Join(dir, "slide.png")
+	diff := filepath.Join(t.TempDir(), "slide.diff.png")
+
+	// The anti-aliased edges of the title are tolerated
+	if err := compareScreenshots(golden, filepath.Join(dir, "slide-antialiased.png"), diff); err != nil {
+		t.Errorf("expected to match: %s", err)
+	}
+	if _, err := os.Stat(diff); !os.IsNotExist(err) {
+		t.Errorf("diff should not be written for the matched screenshots: %v", err)
+	}
+
+	// The text added below the title
+	if err := compareScreenshots(golden, filepath.Join(dir, "slide-changed.png"), diff); err == nil {
+		t.Fatal("expected error for the changed screenshot")
+	}
+	diffImage, err := readPNG(diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := color.RGBAModel.Convert(diffImage.At(30, 32)); c != (color.RGBA{0xff, 0, 0, 0xff}) {
+		t.Errorf("expected the added text in red, but got %v", c)
+	}
+	if c := color.RGBAModel.Convert(diffImage.At(30, 15)); c == (color.RGBA{0xff, 0, 0, 0xff}) {
+		t.Errorf("expected the unchanged title not in red")
+	}
+}
+
+func TestMatchesScreenshots(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake chromium is a shell script")
+	}
+	tmp := t.TempDir()
+	screenshot := filepath.Join(tmp, "screenshot.png")
+	if err := writePNG(screenshot, filledImage(960, 700, color.Black)); err != nil {
+		t.Fatal(err)
+	}
+	chromium := filepath.Join(tmp, "chromium")
+	if err := os.WriteFile(chromium, []byte("#!/bin/sh\nfor arg in \"$@\"; do\n\tcase \"$arg\" in\n\t\t--screenshot=*) cp \""+screenshot+"\" \"${arg#--screenshot=}\" ;;\n\tesac\ndone\n"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(revealjs.EnvChromium, chromium)
+
+	testDir := filepath.Join(tmp, "case")
+	dataDir := filepath.Join(testDir, "testdata")
+	if err := os.MkdirAll(dataDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dataDir, "slides.md"), []byte("# Page 1\n\n---\n\n# Page 2\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	r, err := revealjs.NewRevealJS(dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buildDir := filepath.Join(tmp, "build")
+	if err := r.Build(buildDir); err != nil {
+		t.Fatal(err)
+	}
+	asserter := &BuildResultAsserter{Dir: buildDir, testDir: testDir, revealJS: r}
+
+	// The screenshots directory is not created without -update
+	t.Run("no screenshots", func(t *testing.T) {
+		asserter.MatchesScreenshots(t)
+	})
+	if _, err := os.Stat(filepath.Join(testDir, DirNameScreenshots)); !os.IsNotExist(err) {
+		t.Errorf("screenshots directory should not be created without -update: %v", err)
+	}
+
+	defer func(u bool) { *update = u }(*update)
+	*update = true
+	asserter.MatchesScreenshots(t)
+	for _, name := range []string{"slide-0-0.png", "slide-1-0.png"} {
+		if _, err := os.Stat(filepath.Join(testDir, DirNameScreenshots, name)); err != nil {
+			t.Errorf("golden screenshot %s not created", name)
+		}
+	}
+
+	*update = false
+	asserter.MatchesScreenshots(t)
+	if _, err := os.Stat(filepath.Join(testDir, DirNameScreenshots, "slide-0-0.actual.png")); err == nil {
+		t.Error("the matched screenshot should be removed")
+	}
+}
@@ -0,0 +1,14 @@
+package agenda
+
+import (
+	"testing"
+
+	"github.com/uphy/go-revealjs/test/runner"
+)
+
+func Test(t *testing.T) {
+	runner.Run(t, func(asserter *runner.BuildResultAsserter) {
+		indexHTML := asserter.IndexHTML(t)
+		indexHTML.HasString(t, `<section class="agenda"><h2>Today&#39;s Topics</h2><ul><li><a href="#/2">Architecture</a></li><li><a href="#/3">Roadmap</a></li><li><a href="#/qa">Q&amp;A</a></li></ul></section>`)
+	})
+}
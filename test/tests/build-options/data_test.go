@@ -0,0 +1,33 @@
+package buildoptions
+
+import (
+	"testing"
+
+	"github.com/uphy/go-revealjs/test/runner"
+)
+
+func Test(t *testing.T) {
+	runner.Run(t, func(asserter *runner.BuildResultAsserter) {
+		asserter.HasFile(t, "dist/reveal.js")
+		asserter.HasFile(t, "dist/theme/white.css")
+		asserter.NotHasFile(t, "dist/theme/black.css")
+		asserter.HasFile(t, "plugin/markdown/markdown.js")
+		asserter.HasFile(t, "plugin/notes/notes.js")
+		asserter.NotHasFile(t, "plugin/zoom")
+		asserter.NotHasFile(t, "plugin/highlight/highlight.js")
+		asserter.HasFile(t, "plugin/highlight/monokai.css")
+		asserter.NotHasFile(t, "plugin/highlight/zenburn.css")
+		asserter.HasFile(t, "assets/custom.css")
+		asserter.HasFile(t, "assets/fonts/custom.woff2")
+		asserter.HasFile(t, "assets/images/used.png")
+		asserter.HasFile(t, "assets/images/background.png")
+		asserter.NotHasFile(t, "assets/images/unused.png")
+		asserter.HasFile(t, "assets/images/diagram.svg.png")
+		asserter.NotHasFile(t, "assets/images/diagram.svg")
+		asserter.HasFile(t, "assets/plugins/lazy/lazy.js")
+		asserter.HasFile(t, "assets/plugins/lazy/lang/en.js")
+
+		indexHTML := asserter.IndexHTML(t)
+		indexHTML.HasString(t, `<base href="https://example.com/talks/my-talk/">`)
+	})
+}
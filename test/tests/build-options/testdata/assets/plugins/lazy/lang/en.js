@@ -0,0 +1 @@
+window.RevealLazyMessages = { hello: 'Hello' };
@@ -0,0 +1,9 @@
+window.RevealLazy = {
+	id: 'lazy',
+	init: function(deck) {
+		var lang = deck.getConfig().lazyLang || 'en';
+		var script = document.createElement('script');
+		script.src = 'assets/plugins/lazy/lang/' + lang + '.js';
+		document.head.appendChild(script);
+	}
+};
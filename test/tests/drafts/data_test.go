@@ -0,0 +1,27 @@
+package drafts
+
+import (
+	"testing"
+
+	"github.com/uphy/go-revealjs/test/runner"
+)
+
+func Test(t *testing.T) {
+	runner.Run(t, func(asserter *runner.BuildResultAsserter) {
+		indexHTML := asserter.IndexHTML(t)
+		indexHTML.HasString(t, `<section data-markdown data-separator="^\r?\n---\r?\n$" data-separator-vertical="^\r?\n~~~\r?\n$"># Visible
+
+		---
+
+		# Also visible
+		</section>
+
+		<section>
+		<h2>Visible HTML</h2>
+		</section>`)
+		indexHTML.NotHasString(t, "Unfinished")
+		indexHTML.NotHasString(t, "Hidden markdown")
+		indexHTML.NotHasString(t, "Hidden HTML")
+		indexHTML.NotHasString(t, "DRAFT")
+	})
+}
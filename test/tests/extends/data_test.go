@@ -0,0 +1,17 @@
+package extends
+
+import (
+	"testing"
+
+	"github.com/uphy/go-revealjs/test/runner"
+)
+
+func Test(t *testing.T) {
+	runner.Run(t, func(asserter *runner.BuildResultAsserter) {
+		indexHTML := asserter.IndexHTML(t)
+		indexHTML.HasTitle(t, "My talk")
+		indexHTML.HasTheme(t, "league")
+		indexHTML.HasConfigProperty(t, "controls", "false")
+		indexHTML.HasConfigProperty(t, "progress", "false")
+	})
+}
@@ -0,0 +1,21 @@
+package order
+
+import (
+	"testing"
+
+	"github.com/uphy/go-revealjs/test/runner"
+)
+
+func Test(t *testing.T) {
+	runner.Run(t, func(asserter *runner.BuildResultAsserter) {
+		indexHTML := asserter.IndexHTML(t)
+		indexHTML.HasString(t, `<section data-markdown data-separator="^\r?\n---\r?\n$" data-separator-vertical="^\r?\n~~~\r?\n$"># Two
+		</section>
+
+		<section data-markdown data-separator="^\r?\n---\r?\n$" data-separator-vertical="^\r?\n~~~\r?\n$"># Ten
+		</section>
+
+		<section data-markdown data-separator="^\r?\n---\r?\n$" data-separator-vertical="^\r?\n~~~\r?\n$"># One last
+		</section>`)
+	})
+}
@@ -0,0 +1,17 @@
+package overrides
+
+import (
+	"testing"
+
+	"github.com/uphy/go-revealjs/test/runner"
+)
+
+func Test(t *testing.T) {
+	runner.RunWithOverrides(t, []string{"title=Overridden: title", "theme=white", "revealjs.controls=false", "revealjs.width=1280"}, func(asserter *runner.BuildResultAsserter) {
+		indexHTML := asserter.IndexHTML(t)
+		indexHTML.HasTitle(t, "Overridden: title")
+		indexHTML.HasTheme(t, "white")
+		indexHTML.HasConfigProperty(t, "controls", "false")
+		indexHTML.HasConfigProperty(t, "width", "1280")
+	})
+}
@@ -0,0 +1,39 @@
+package profiles
+
+import (
+	"testing"
+
+	"github.com/uphy/go-revealjs/test/runner"
+)
+
+func Test(t *testing.T) {
+	runner.RunWithProfile(t, "public", func(asserter *runner.BuildResultAsserter) {
+		indexHTML := asserter.IndexHTML(t)
+		indexHTML.HasTitle(t, "Public talk")
+		indexHTML.HasTheme(t, "white")
+		indexHTML.HasConfigProperty(t, "controls", "false")
+		indexHTML.HasString(t, `<section data-markdown data-separator="^\r?\n---\r?\n$" data-separator-vertical="^\r?\n~~~\r?\n$"># Welcome
+		</section>
+
+		<section>
+		<h2>Product</h2>
+		</section>`)
+		indexHTML.NotHasString(t, "Revenue")
+		indexHTML.NotHasString(t, "Incidents")
+		indexHTML.HasString(t, "# Roadmap")
+		indexHTML.NotHasString(t, "Internal secret")
+	})
+}
+
+func TestServe(t *testing.T) {
+	runner.ServeWithProfile(t, "public", func(asserter *runner.ServerAsserter) {
+		asserter.HasContent(t, "/", "<title>Public talk</title>")
+		asserter.HasContent(t, "/", `data-markdown="slides/04-roadmap.md"`)
+		asserter.NotHasContent(t, "/", `data-markdown="slides/02-revenue.md"`)
+
+		// The slides excluded by the profile are not served
+		asserter.HasContent(t, "/slides/04-roadmap.md", "# Roadmap")
+		asserter.NotHasContent(t, "/slides/04-roadmap.md", "Internal secret")
+		asserter.NotFound(t, "/slides/02-revenue.md")
+	})
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/uphy/go-revealjs/test/runner"
+)
+
+func Test(t *testing.T) {
+	runner.Serve(t, func(asserter *runner.ServerAsserter) {
+		asserter.HasContent(t, "/", "<title>Dev server</title>")
+		asserter.HasContent(t, "/", asserter.Revision(t))
+		asserter.HasContent(t, "/slides.md", "# Welcome")
+		asserter.NotHasContent(t, "/slides.md", "order: 1")
+		asserter.HasContent(t, "/assets/logo.txt", "logo")
+		asserter.HasRevealJSFile(t, "/dist/reveal.js")
+
+		// Outside of the slide resources
+		asserter.NotFound(t, "/secret.txt")
+		asserter.NotFound(t, "/slides/secret.txt")
+		asserter.NotFound(t, "/missing.md")
+		asserter.NotFound(t, "/notes/private.md")
+
+		asserter.WriteFile(t, "slides.md", "---\norder: 2\n---\n# Updated\n")
+		asserter.HasContent(t, "/slides.md", "# Updated")
+		asserter.NotHasContent(t, "/slides.md", "order: 2")
+
+		asserter.WriteFile(t, "config.yml", "title: Reloaded\n")
+		asserter.HasContent(t, "/", "<title>Reloaded</title>")
+		asserter.HasContent(t, "/", asserter.Revision(t))
+
+		asserter.WriteFile(t, "slides/02.md", "# Second\n")
+		asserter.HasContent(t, "/", `data-markdown="slides/02.md"`)
+		asserter.HasContent(t, "/slides/02.md", "# Second")
+
+		asserter.RemoveFile(t, "slides/02.md")
+		asserter.NotHasContent(t, "/", `data-markdown="slides/02.md"`)
+		asserter.NotFound(t, "/slides/02.md")
+
+		// The stale entries of 'slides' are skipped
+		asserter.WriteFile(t, "config.yml", "title: Stale slides\nslides:\n  - slides.md\n  - missing.md\n")
+		asserter.HasContent(t, "/", "<title>Stale slides</title>")
+		asserter.HasContent(t, "/", `data-markdown="slides.md"`)
+	})
+}
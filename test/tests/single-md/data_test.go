@@ -1,6 +1,7 @@
 package singlemd
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/uphy/go-revealjs/test/runner"
@@ -12,25 +13,21 @@ func Test(t *testing.T) {
 		asserter.NotHasFile(t, "*.md")
 
 		indexHTML := asserter.IndexHTML(t)
-		indexHTML.HasString(t, `<section data-markdown data-separator="^\r?\n---\r?\n$" data-separator-vertical="^\r?\n~~~\r?\n$"># Page 1
-        
-			foo
-			
-			---
-			
-			# Page 2
-			
-			bar</section>`)
 		indexHTML.HasTitle(t, "reveal.js")
 		indexHTML.HasTheme(t, "black")
 		indexHTML.HasStandardScriptTags(t)
-		indexHTML.HasConfigProperty(t, "plugins", `[
-                                        RevealMarkdown,
-                                        RevealHighlight,
-                                        RevealSearch,
-                                        RevealNotes,
-                                        RevealMath,
-                                        RevealZoom,
-                                ]`)
+
+		indexHTML.SectionCount(t, 1)
+		section := indexHTML.Section(t, 0)
+		section.HasAttr(t, "data-markdown", "")
+		section.HasAttr(t, "data-separator", `^\r?\n---\r?\n$`)
+		section.HasAttr(t, "data-separator-vertical", `^\r?\n~~~\r?\n$`)
+		section.HasText(t, "# Page 1\n\nfoo\n\n---\n\n# Page 2\n\nbar")
+		if plugins := strings.Join(indexHTML.Plugins(t), ","); plugins != "RevealMarkdown,RevealHighlight,RevealSearch,RevealNotes,RevealMath,RevealZoom" {
+			t.Errorf("unexpected plugins: %s", plugins)
+		}
+		if hash := indexHTML.RevealConfig(t)["hash"]; hash != true {
+			t.Errorf("expected hash to be true but %v", hash)
+		}
 	})
 }
@@ -0,0 +1,26 @@
+package slidespatterns
+
+import (
+	"testing"
+
+	"github.com/uphy/go-revealjs/test/runner"
+)
+
+func Test(t *testing.T) {
+	runner.Run(t, func(asserter *runner.BuildResultAsserter) {
+		indexHTML := asserter.IndexHTML(t)
+		indexHTML.HasString(t, `<section data-markdown data-separator="^\r?\n---\r?\n$" data-separator-vertical="^\r?\n~~~\r?\n$"># Closing
+		</section>
+
+		<section>
+		<section data-markdown data-separator="^\r?\n(?:---|~~~)\r?\n$"># Welcome
+		</section>
+		<section data-markdown data-separator="^\r?\n(?:---|~~~)\r?\n$"># Agenda
+		</section>
+		</section>
+
+		<section data-markdown data-separator="^\r?\n---\r?\n$" data-separator-vertical="^\r?\n~~~\r?\n$"># Body
+		</section>`)
+		indexHTML.NotHasString(t, "# Draft")
+	})
+}
@@ -0,0 +1,45 @@
+package verticalstack
+
+import (
+	"testing"
+
+	"github.com/uphy/go-revealjs/test/runner"
+)
+
+func Test(t *testing.T) {
+	runner.Run(t, func(asserter *runner.BuildResultAsserter) {
+		indexHTML := asserter.IndexHTML(t)
+		indexHTML.HasString(t, `<section data-markdown data-separator="^\r?\n---\r?\n$" data-separator-vertical="^\r?\n~~~\r?\n$"># Intro
+		</section>
+
+		<section>
+		<section data-markdown data-separator="^\r?\n(?:---|~~~)\r?\n$"># Frontend
+
+		---
+
+		# Backend
+		</section>
+		<section>
+		<h2>Queue</h2>
+		</section>
+
+		<section>
+		<h2>Database</h2>
+		</section>
+
+		</section>
+
+		<section data-markdown data-separator="^\r?\n---\r?\n$" data-separator-vertical="^\r?\n~~~\r?\n$"># Roadmap
+		</section>`)
+
+		indexHTML.SectionCount(t, 4)
+		indexHTML.Section(t, 0).HasText(t, "# Intro")
+		stack := indexHTML.Section(t, 1)
+		stack.NotHasAttr(t, "data-markdown")
+		stack.SectionCount(t, 3)
+		stack.Section(t, 0).HasAttr(t, "data-separator", `^\r?\n(?:---|~~~)\r?\n$`)
+		stack.Section(t, 0).NotHasAttr(t, "data-separator-vertical")
+		stack.Section(t, 2).HasText(t, "Database")
+		indexHTML.Section(t, 2).HasAttr(t, "data-markdown", "")
+	})
+}
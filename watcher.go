@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
@@ -16,32 +17,58 @@ type Watcher struct {
 	Revision      *Revision
 }
 
+// Revision changes when the data directory is updated, to reload the slides in the browser.
 type Revision struct {
-	Value string
+	mu    sync.RWMutex
+	value string
+	// clock returns the current time which the revision is made from.
+	clock func() time.Time
+}
+
+// String returns the current revision.
+func (r *Revision) String() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.value
 }
 
 func (r *Revision) update() {
-	r.Value = time.Now().String()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.value = r.clock().String()
 }
 
 func NewWatcher(dataDirectory string, onUpdate func()) (*Watcher, error) {
+	return NewWatcherWithClock(dataDirectory, onUpdate, time.Now)
+}
+
+// NewWatcherWithClock creates the watcher making the revisions from the time returned by clock.
+func NewWatcherWithClock(dataDirectory string, onUpdate func(), clock func() time.Time) (*Watcher, error) {
 	w, err := fsnotify.NewWatcher()
 	if err != nil {
 		return nil, err
 	}
-	rev := &Revision{}
+	rev := &Revision{clock: clock}
 	rev.update()
 	return &Watcher{w, dataDirectory, onUpdate, rev}, err
 }
 
-func (w *Watcher) Start() {
-	w.watcher.Add(w.dataDirectory)
+// Start watches the directories in the data directory, and notifies the updates in the background until Close is called.
+func (w *Watcher) Start() error {
+	if err := w.watcher.Add(w.dataDirectory); err != nil {
+		return err
+	}
 	filepath.Walk(w.dataDirectory, func(path string, info os.FileInfo, err error) error {
-		if info.IsDir() {
+		if err == nil && info.IsDir() {
 			w.watcher.Add(path)
 		}
 		return nil
 	})
+	go w.run()
+	return nil
+}
+
+func (w *Watcher) run() {
 	for evt := range w.watcher.Events {
 		op := evt.Op
 		if op&fsnotify.Create != 0 {
@@ -58,6 +85,11 @@ func (w *Watcher) Start() {
 	}
 }
 
+// Close stops watching the data directory.
+func (w *Watcher) Close() error {
+	return w.watcher.Close()
+}
+
 func (w *Watcher) notifyUpdate() {
 	log.Println("Data directory updated.")
 	w.onUpdate()